@@ -2,4 +2,6 @@ module github.com/EcomPlatformOrg/ecpay-go
 
 go 1.21
 
-require github.com/goccy/go-reflect v1.2.0 // indirect
+require github.com/goccy/go-reflect v1.2.0
+
+require golang.org/x/text v0.14.0
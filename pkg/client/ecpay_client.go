@@ -1,7 +1,93 @@
 package client
 
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultTimeout 未自訂 HTTPClient 時, 單次請求的逾時時間
+const DefaultTimeout = 30 * time.Second
+
+// DefaultDownloadTimeout 未自訂 HTTPClient 時, 下載對帳檔等大型回應的逾時時間
+const DefaultDownloadTimeout = 10 * time.Minute
+
+// Doer is the minimal interface of an HTTP client used by the SDK.
+// *http.Client satisfies it, so custom TLS, proxy or retry settings can be injected.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ErrNoEnvironment 未設定 BaseURL 也未設定 Environment, 無法決定請求網址
+var ErrNoEnvironment = errors.New("ECPayClient has neither BaseURL nor Environment; set Environment to client.Stage or client.Production")
+
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout}
+
+// downloadHTTPClient 下載對帳檔等大型回應時使用, 逾時較長, 呼叫端的 context 可再縮短期限
+var downloadHTTPClient = &http.Client{Timeout: DefaultDownloadTimeout}
+
 type ECPayClient struct {
+	// MerchantID 預設特店編號, 請求未帶 MerchantID 時使用
+	MerchantID string `json:"MerchantID,omitempty"`
+
+	// BaseURL 指定請求網址, 設定時優先於 Environment (相容舊版用法)
 	BaseURL string `json:"BaseURL"`
 	HashKey string `json:"HashKey"`
 	HashIV  string `json:"HashIV"`
+
+	// HTTPClient 發送請求使用的 HTTP 客戶端, 未設定時使用逾時為 DefaultTimeout 的 http.Client
+	HTTPClient Doer `json:"-"`
+
+	// Environment 串接環境, 須明確指定 Stage 或 Production
+	Environment *Environment `json:"Environment,omitempty"`
+
+	// Logger SDK 使用的日誌, 未設定時不輸出任何日誌; 輸出內容會自動遮蔽金鑰與個人資料
+	Logger *slog.Logger `json:"-"`
+
+	// TraceSigning 以 Debug 層級記錄 CheckMacValue 的產生步驟
+	TraceSigning bool `json:"-"`
+}
+
+// URL resolves the endpoint of api. An explicit BaseURL wins for backward compatibility,
+// otherwise the URL is looked up in the client's Environment. There is no default
+// environment: ErrNoEnvironment is returned when neither is set, so a production
+// merchant cannot end up on the stage hosts by omission.
+func (c *ECPayClient) URL(api API) (string, error) {
+	if c.BaseURL != "" {
+		return c.BaseURL, nil
+	}
+	if c.Environment != nil {
+		return c.Environment.URL(api), nil
+	}
+	return "", ErrNoEnvironment
+}
+
+// DefaultMerchantID sets MerchantID in values to the client's MerchantID when the
+// request left it empty, so a client built from a Credential can sign requests
+// without repeating the merchant on each of them.
+func (c *ECPayClient) DefaultMerchantID(values url.Values) {
+	if values.Get("MerchantID") == "" && c.MerchantID != "" {
+		values.Set("MerchantID", c.MerchantID)
+	}
+}
+
+// Doer returns the HTTP client used to send requests to ECPay.
+func (c *ECPayClient) Doer() Doer {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return defaultHTTPClient
+}
+
+// DownloadDoer returns the HTTP client used for streamed downloads such as reconciliation
+// reports. A custom HTTPClient is used as is; otherwise the client times out after
+// DefaultDownloadTimeout, so a large report is not cut off after DefaultTimeout while a
+// stalled download without a context deadline still ends.
+func (c *ECPayClient) DownloadDoer() Doer {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return downloadHTTPClient
 }
@@ -0,0 +1,213 @@
+package client
+
+// Product 綠界服務類別, 不同服務使用不同主機
+type Product int
+
+const (
+	// ProductPayment 金流 (全方位金流)
+	ProductPayment Product = iota
+
+	// ProductLogistics 物流
+	ProductLogistics
+
+	// ProductEInvoice 電子發票
+	ProductEInvoice
+
+	// ProductVendor 特店管理後台 (對帳媒體檔下載)
+	ProductVendor
+)
+
+// API identifies one ECPay endpoint: the product host it lives on and its path.
+type API struct {
+	Product Product
+	Path    string
+}
+
+var (
+	// APIAioCheckOut 全方位金流 產生訂單
+	APIAioCheckOut = API{Product: ProductPayment, Path: "/Cashier/AioCheckOut/V5"}
+
+	// APIQueryTradeInfo 查詢訂單
+	APIQueryTradeInfo = API{Product: ProductPayment, Path: "/Cashier/QueryTradeInfo/V5"}
+
+	// APIQueryPaymentInfo 查詢 ATM / CVS / BARCODE 取號結果
+	APIQueryPaymentInfo = API{Product: ProductPayment, Path: "/Cashier/QueryPaymentInfo"}
+
+	// APICreditDoAction 信用卡請退款
+	APICreditDoAction = API{Product: ProductPayment, Path: "/CreditDetail/DoAction"}
+
+	// APICreditQueryTrade 查詢信用卡單筆明細
+	APICreditQueryTrade = API{Product: ProductPayment, Path: "/CreditDetail/QueryTrade/V2"}
+
+	// APIFundingReconDetail 下載信用卡撥款對帳資料檔
+	APIFundingReconDetail = API{Product: ProductPayment, Path: "/CreditDetail/FundingReconDetail"}
+
+	// APITradeNoAio 下載特店對帳媒體檔
+	APITradeNoAio = API{Product: ProductVendor, Path: "/PaymentMedia/TradeNoAio"}
+
+	// APIQueryCreditCardPeriodInfo 信用卡定期定額訂單查詢
+	APIQueryCreditCardPeriodInfo = API{Product: ProductPayment, Path: "/Cashier/QueryCreditCardPeriodInfo"}
+
+	// APILogisticsMap 電子地圖選擇門市
+	APILogisticsMap = API{Product: ProductLogistics, Path: "/Express/map"}
+
+	// APILogisticsCreate 物流訂單建立
+	APILogisticsCreate = API{Product: ProductLogistics, Path: "/Express/Create"}
+
+	// APILogisticsReturnHome 宅配逆物流訂單
+	APILogisticsReturnHome = API{Product: ProductLogistics, Path: "/Express/ReturnHome"}
+
+	// APILogisticsReturnFAMI 全家 B2C 逆物流訂單
+	APILogisticsReturnFAMI = API{Product: ProductLogistics, Path: "/express/ReturnCVS"}
+
+	// APILogisticsReturnUniMart 7-ELEVEN B2C 逆物流訂單
+	APILogisticsReturnUniMart = API{Product: ProductLogistics, Path: "/express/ReturnUniMartCVS"}
+
+	// APILogisticsReturnHiLife 萊爾富 B2C 逆物流訂單
+	APILogisticsReturnHiLife = API{Product: ProductLogistics, Path: "/express/ReturnHiLifeCVS"}
+
+	// APILogisticsQueryTradeInfo 查詢物流訂單
+	APILogisticsQueryTradeInfo = API{Product: ProductLogistics, Path: "/Helper/QueryLogisticsTradeInfo/V4"}
+
+	// APILogisticsUpdateShipmentInfo 更新出貨日期與取件門市 (B2C)
+	APILogisticsUpdateShipmentInfo = API{Product: ProductLogistics, Path: "/Helper/UpdateShipmentInfo"}
+
+	// APILogisticsUpdateStoreInfo 更新取件 / 退件門市 (C2C)
+	APILogisticsUpdateStoreInfo = API{Product: ProductLogistics, Path: "/Express/UpdateStoreInfo"}
+
+	// APILogisticsCancelC2COrder 取消店到店訂單
+	APILogisticsCancelC2COrder = API{Product: ProductLogistics, Path: "/Express/CancelC2COrder"}
+
+	// APILogisticsRedirectToSelection 全方位物流 物流選擇頁
+	APILogisticsRedirectToSelection = API{Product: ProductLogistics, Path: "/Express/v2/RedirectToLogisticsSelection"}
+
+	// APILogisticsUpdateTempTrade 全方位物流 更新暫存物流訂單
+	APILogisticsUpdateTempTrade = API{Product: ProductLogistics, Path: "/Express/v2/UpdateTempTrade"}
+
+	// APILogisticsCreateByTempTrade 全方位物流 建立正式物流訂單
+	APILogisticsCreateByTempTrade = API{Product: ProductLogistics, Path: "/Express/v2/CreateByTempTrade"}
+
+	// APILogisticsCreateTestData 全方位物流 產生測試標籤資料
+	APILogisticsCreateTestData = API{Product: ProductLogistics, Path: "/Express/v2/CreateTestData"}
+
+	// APILogisticsQueryTradeInfoV2 全方位物流 查詢物流訂單
+	APILogisticsQueryTradeInfoV2 = API{Product: ProductLogistics, Path: "/Express/v2/QueryLogisticsTradeInfo"}
+
+	// APILogisticsPrintTradeDocumentV2 全方位物流 列印託運單
+	APILogisticsPrintTradeDocumentV2 = API{Product: ProductLogistics, Path: "/Express/v2/PrintTradeDocument"}
+
+	// APILogisticsPrintTradeDocument 列印 B2C / 宅配 託運單
+	APILogisticsPrintTradeDocument = API{Product: ProductLogistics, Path: "/helper/printTradeDocument"}
+
+	// APILogisticsPrintUniMartC2C 列印 7-ELEVEN 交貨便託運單
+	APILogisticsPrintUniMartC2C = API{Product: ProductLogistics, Path: "/Express/PrintUniMartC2COrderInfo"}
+
+	// APILogisticsPrintFAMIC2C 列印全家店到店託運單
+	APILogisticsPrintFAMIC2C = API{Product: ProductLogistics, Path: "/Express/PrintFAMIC2COrderInfo"}
+
+	// APILogisticsPrintHILIFEC2C 列印萊爾富店到店託運單
+	APILogisticsPrintHILIFEC2C = API{Product: ProductLogistics, Path: "/Express/PrintHILIFEC2COrderInfo"}
+
+	// APILogisticsPrintOKMARTC2C 列印OK超商店到店託運單
+	APILogisticsPrintOKMARTC2C = API{Product: ProductLogistics, Path: "/Express/PrintOKMARTC2COrderInfo"}
+)
+
+// Environment holds the host of every ECPay product for one deployment (stage, production or custom).
+type Environment struct {
+	Name string `json:"Name"`
+
+	// PaymentHost 金流主機
+	PaymentHost string `json:"PaymentHost"`
+
+	// LogisticsHost 物流主機
+	LogisticsHost string `json:"LogisticsHost"`
+
+	// EInvoiceHost 電子發票主機
+	EInvoiceHost string `json:"EInvoiceHost"`
+
+	// VendorHost 特店管理後台主機
+	VendorHost string `json:"VendorHost"`
+}
+
+var (
+	// Stage 綠界測試環境
+	Stage = Environment{
+		Name:          "stage",
+		PaymentHost:   "https://payment-stage.ecpay.com.tw",
+		LogisticsHost: "https://logistics-stage.ecpay.com.tw",
+		EInvoiceHost:  "https://einvoice-stage.ecpay.com.tw",
+		VendorHost:    "https://vendor-stage.ecpay.com.tw",
+	}
+
+	// Production 綠界正式環境
+	Production = Environment{
+		Name:          "production",
+		PaymentHost:   "https://payment.ecpay.com.tw",
+		LogisticsHost: "https://logistics.ecpay.com.tw",
+		EInvoiceHost:  "https://einvoice.ecpay.com.tw",
+		VendorHost:    "https://vendor.ecpay.com.tw",
+	}
+)
+
+// Custom returns an environment that routes every product to host,
+// e.g. a local mock server or an outbound proxy.
+func Custom(host string) Environment {
+	return Environment{
+		Name:          "custom",
+		PaymentHost:   host,
+		LogisticsHost: host,
+		EInvoiceHost:  host,
+		VendorHost:    host,
+	}
+}
+
+// Host returns the host serving product p.
+func (env Environment) Host(p Product) string {
+	switch p {
+	case ProductLogistics:
+		return env.LogisticsHost
+	case ProductEInvoice:
+		return env.EInvoiceHost
+	case ProductVendor:
+		return env.VendorHost
+	default:
+		return env.PaymentHost
+	}
+}
+
+// URL returns the full endpoint URL of api in this environment.
+func (env Environment) URL(api API) string {
+	return env.Host(api.Product) + api.Path
+}
+
+// Credential 特店串接資訊
+type Credential struct {
+	MerchantID string `json:"MerchantID"`
+	HashKey    string `json:"HashKey"`
+	HashIV     string `json:"HashIV"`
+}
+
+// 綠界公開的測試特店資料, 僅能用於 Stage 環境
+var (
+	// StagePayment 全方位金流 測試特店
+	StagePayment = Credential{MerchantID: "3002607", HashKey: "pwFHCqoQZGmho4w6", HashIV: "EkRm7iFT261dpevs"}
+
+	// StageLogisticsB2C 物流 B2C / 宅配 測試特店
+	StageLogisticsB2C = Credential{MerchantID: "2000132", HashKey: "5294y06JbISpM5x9", HashIV: "v77hoKGq4kWxNNIS"}
+
+	// StageLogisticsC2C 物流 C2C 測試特店
+	StageLogisticsC2C = Credential{MerchantID: "2000933", HashKey: "XBERn1YOvpM9nfZc", HashIV: "h1ONHk4P4yqbl5LK"}
+
+	// StageEInvoice 電子發票 測試特店
+	StageEInvoice = Credential{MerchantID: "2000132", HashKey: "ejCk326UnaZWKisg", HashIV: "q9jcZX8Ib9LM8wYk"}
+)
+
+// Client returns an ECPayClient signing with these credentials against env.
+func (c Credential) Client(env Environment) *ECPayClient {
+	return &ECPayClient{
+		MerchantID:  c.MerchantID,
+		HashKey:     c.HashKey,
+		HashIV:      c.HashIV,
+		Environment: &env,
+	}
+}
@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted 取代敏感資料的字串
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyNames 依欄位名稱遮蔽的參數 (不分大小寫). Attribute keys and the
+// key/value pairs found inside messages are matched against this single list.
+var sensitiveKeyNames = []string{
+	"hashkey",
+	"hashiv",
+	"checkmacvalue",
+	"sendername",
+	"senderphone",
+	"sendercellphone",
+	"senderemail",
+	"senderaddress",
+	"receivername",
+	"receiverphone",
+	"receivercellphone",
+	"receiveremail",
+	"receiveraddress",
+	"card4no",
+	"card6no",
+	"email",
+	"phone",
+	"address",
+}
+
+var sensitiveKeys = func() map[string]bool {
+	keys := make(map[string]bool, len(sensitiveKeyNames))
+	for _, name := range sensitiveKeyNames {
+		keys[name] = true
+	}
+	return keys
+}()
+
+var sensitiveNames = func() string {
+	quoted := make([]string, len(sensitiveKeyNames))
+	for i, name := range sensitiveKeyNames {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return strings.Join(quoted, "|")
+}()
+
+var (
+	// key=value 形式 (表單 / 簽章字串)
+	formPairPattern = regexp.MustCompile(`(?i)\b(` + sensitiveNames + `)(=)[^&]*`)
+
+	// key%3Dvalue%26 形式 (URL encode 後的簽章字串)
+	encodedPairPattern = regexp.MustCompile(`(?i)(` + sensitiveNames + `)(%3d).*?(%26|$)`)
+
+	// "key":"value" 形式 (JSON)
+	jsonPairPattern = regexp.MustCompile(`(?i)"(` + sensitiveNames + `)"\s*:\s*"[^"]*"`)
+
+	// key:value 形式 (fmt 輸出的 map / struct)
+	colonPairPattern = regexp.MustCompile(`(?i)\b(` + sensitiveNames + `)(:)[^\s\]},]*`)
+
+	emailPattern    = regexp.MustCompile(`(?i)[a-z0-9._+\-]+(?:@|%40)[a-z0-9\-]+(?:\.[a-z0-9\-]+)+`)
+	mobilePattern   = regexp.MustCompile(`(?:\+886-?|\b0)9\d{2}[- ]?\d{3}[- ]?\d{3}\b`)
+	landlinePattern = regexp.MustCompile(`(?:\(0\d{1,3}\)|\b0[2-8]\d{0,2})[- ]?\d{2,4}[- ]?\d{4}\b`)
+	cardPattern     = regexp.MustCompile(`\b\d{4}[- ]\d{4}[- ]\d{4}[- ]\d{1,7}\b|\b\d{4,6}\*{2,}\d{4}\b`)
+	panPattern      = regexp.MustCompile(`\b\d{13,19}\b`)
+	addressPattern  = regexp.MustCompile(`\p{Han}{1,3}[市縣][\p{Han}\d]*?[路街道][\p{Han}\d段巷弄之\-]*號[\p{Han}\dF之\-]*`)
+)
+
+// maxRedactDepth 結構化遮蔽時遞迴的最大深度
+const maxRedactDepth = 8
+
+// redactingLoggerKey identifies the redacting wrapper built for one configured Logger and key pair.
+type redactingLoggerKey struct {
+	from    *slog.Logger
+	hashKey string
+	hashIV  string
+}
+
+// redactingLoggers 快取 Log() 建立的遮蔽日誌. Kept outside ECPayClient so that the
+// client stays safe to copy by value.
+var redactingLoggers sync.Map
+
+// Log returns the SDK logger of this client. Every line it writes has the client's
+// HashKey/HashIV and personal data (phones, emails, addresses, card fragments) redacted.
+// Without a configured Logger all output is discarded.
+// The redacting wrapper is built once per Logger, HashKey and HashIV and then reused.
+func (c *ECPayClient) Log() *slog.Logger {
+	if c == nil || c.Logger == nil {
+		return DiscardLogger()
+	}
+
+	key := redactingLoggerKey{from: c.Logger, hashKey: c.HashKey, hashIV: c.HashIV}
+	if cached, ok := redactingLoggers.Load(key); ok {
+		return cached.(*slog.Logger)
+	}
+
+	built, _ := redactingLoggers.LoadOrStore(key, slog.New(NewRedactingHandler(c.Logger.Handler(), c.HashKey, c.HashIV)))
+	return built.(*slog.Logger)
+}
+
+// SigningLog returns the logger used to trace CheckMacValue signing steps.
+// Signing is only traced when TraceSigning is enabled.
+func (c *ECPayClient) SigningLog() *slog.Logger {
+	if c == nil || !c.TraceSigning {
+		return DiscardLogger()
+	}
+	return c.Log()
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+// DiscardLogger returns a logger that drops every record.
+func DiscardLogger() *slog.Logger {
+	return discardLogger
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// NewRedactingHandler wraps next so that secrets and personal data are masked in
+// the message and in every attribute before they reach next.
+func NewRedactingHandler(next slog.Handler, secrets ...string) slog.Handler {
+	r := &redactor{}
+	for _, s := range secrets {
+		if s == "" {
+			continue
+		}
+		// 簽章過程中金鑰會以原文、URL encode 與小寫形式出現
+		r.secrets = append(r.secrets, s, strings.ToLower(s), url.QueryEscape(s), strings.ToLower(url.QueryEscape(s)))
+	}
+	return &redactingHandler{next: next, redactor: r}
+}
+
+type redactingHandler struct {
+	next     slog.Handler
+	redactor *redactor
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.String(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.Attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redactor.Attr(attr))
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), redactor: h.redactor}
+}
+
+type redactor struct {
+	secrets []string
+}
+
+// Attr masks the value of attr, recursing into groups.
+func (r *redactor) Attr(attr slog.Attr) slog.Attr {
+	return r.attr(attr, 0)
+}
+
+func (r *redactor) attr(attr slog.Attr, depth int) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, 0, len(group))
+		for _, a := range group {
+			redacted = append(redacted, r.attr(a, depth+1))
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindString:
+		return slog.String(attr.Key, r.String(value.String()))
+	case slog.KindAny:
+		return slog.Attr{Key: attr.Key, Value: r.any(value.Any(), depth)}
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+// any masks an arbitrary value structurally: map entries and struct fields are
+// redacted by key like attributes, so that a sensitive field never reaches the
+// output through fmt's key:value rendering. Other values are masked as strings.
+func (r *redactor) any(v any, depth int) slog.Value {
+
+	switch x := v.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case error:
+		return slog.StringValue(r.String(x.Error()))
+	case time.Time:
+		return slog.TimeValue(x)
+	case []byte:
+		return slog.StringValue(r.String(string(x)))
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		rv = rv.Elem()
+	}
+	if depth >= maxRedactDepth {
+		return slog.StringValue(Redacted)
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		attrs := make([]slog.Attr, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			attrs = append(attrs, r.attr(slog.Any(fmt.Sprint(iter.Key().Interface()), iter.Value().Interface()), depth+1))
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Struct:
+		if stringer, ok := v.(fmt.Stringer); ok {
+			return slog.StringValue(r.String(stringer.String()))
+		}
+		attrs := make([]slog.Attr, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			attrs = append(attrs, r.attr(slog.Any(field.Name, rv.Field(i).Interface()), depth+1))
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Slice, reflect.Array:
+		attrs := make([]slog.Attr, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			attrs = append(attrs, r.attr(slog.Any(strconv.Itoa(i), rv.Index(i).Interface()), depth+1))
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.String:
+		return slog.StringValue(r.String(rv.String()))
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return slog.StringValue(r.String(fmt.Sprint(rv.Interface())))
+	default:
+		return slog.StringValue(r.String(fmt.Sprintf("%v", v)))
+	}
+}
+
+// String masks secrets and personal data found in s.
+func (r *redactor) String(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	s = formPairPattern.ReplaceAllString(s, "$1$2"+Redacted)
+	s = encodedPairPattern.ReplaceAllString(s, "$1$2"+Redacted+"$3")
+	s = jsonPairPattern.ReplaceAllString(s, `"$1":"`+Redacted+`"`)
+	s = colonPairPattern.ReplaceAllString(s, "$1$2"+Redacted)
+	s = emailPattern.ReplaceAllString(s, Redacted)
+	s = cardPattern.ReplaceAllString(s, Redacted)
+	s = panPattern.ReplaceAllStringFunc(s, func(digits string) string {
+		if looksLikePAN(digits) {
+			return Redacted
+		}
+		return digits
+	})
+	s = mobilePattern.ReplaceAllString(s, Redacted)
+	s = landlinePattern.ReplaceAllString(s, Redacted)
+	s = addressPattern.ReplaceAllString(s, Redacted)
+	return s
+}
+
+// looksLikePAN reports whether an unseparated 13-19 digit run may be a card number:
+// it starts with a card network digit (3 JCB/AMEX, 4 VISA, 5 Mastercard, 6 UnionPay) or
+// passes the Luhn check. ECPay TradeNo starts with the year (yyMMdd...) and is kept
+// unless it happens to pass the Luhn check.
+func looksLikePAN(digits string) bool {
+	if digits[0] >= '3' && digits[0] <= '6' {
+		return true
+	}
+	return luhnValid(digits)
+}
+
+// luhnValid reports whether digits passes the Luhn check of card numbers.
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"bytes"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestClient(buf *bytes.Buffer) *ECPayClient {
+	return &ECPayClient{
+		HashKey: "pwFHCqoQZGmho4w6",
+		HashIV:  "EkRm7iFT261dpevs",
+		Logger:  slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+}
+
+type receiver struct {
+	ReceiverName string
+	GoodsName    string
+}
+
+type order struct {
+	MerchantTradeNo string
+	receiver
+	Receiver receiver
+}
+
+func TestRedactingLogger(t *testing.T) {
+	tests := []struct {
+		name   string
+		log    func(*slog.Logger)
+		leaked []string
+		kept   []string
+	}{
+		{
+			name:   "secrets in message",
+			log:    func(l *slog.Logger) { l.Info("HashKey=pwFHCqoQZGmho4w6&HashIV=EkRm7iFT261dpevs") },
+			leaked: []string{"pwFHCqoQZGmho4w6", "EkRm7iFT261dpevs"},
+		},
+		{
+			name: "lowercase url-encoded secret",
+			log: func(l *slog.Logger) {
+				l.Debug("step", "encodedString", "hashkey%3dpwfhcqoqzgmho4w6%26merchantid%3d3002607")
+			},
+			leaked: []string{"pwfhcqoqzgmho4w6"},
+			kept:   []string{"3002607"},
+		},
+		{
+			name:   "sensitive attribute key",
+			log:    func(l *slog.Logger) { l.Info("send", "ReceiverName", "王小明", "MerchantID", "3002607") },
+			leaked: []string{"王小明"},
+			kept:   []string{"3002607"},
+		},
+		{
+			name: "map attribute",
+			log: func(l *slog.Logger) {
+				l.Info("send", "nested", map[string]string{"ReceiverName": "王小明", "GoodsName": "書"})
+			},
+			leaked: []string{"王小明"},
+			kept:   []string{"書"},
+		},
+		{
+			name: "url values attribute",
+			log: func(l *slog.Logger) {
+				l.Info("send", "form", url.Values{"SenderName": {"陳大文"}, "GoodsAmount": {"200"}})
+			},
+			leaked: []string{"陳大文"},
+			kept:   []string{"200"},
+		},
+		{
+			name: "struct attribute",
+			log: func(l *slog.Logger) {
+				l.Info("send", "order", &order{MerchantTradeNo: "A1", Receiver: receiver{ReceiverName: "王小明", GoodsName: "書"}})
+			},
+			leaked: []string{"王小明"},
+			kept:   []string{"A1", "書"},
+		},
+		{
+			name:   "fmt rendered map in message",
+			log:    func(l *slog.Logger) { l.Info("nested=map[ReceiverName:王小明]") },
+			leaked: []string{"王小明"},
+		},
+		{
+			name:   "form pair",
+			log:    func(l *slog.Logger) { l.Info("ReceiverCellPhone=0912345678&GoodsName=book") },
+			leaked: []string{"0912345678"},
+			kept:   []string{"book"},
+		},
+		{
+			name:   "json pair",
+			log:    func(l *slog.Logger) { l.Info(`{"ReceiverEmail":"a@b.tw","GoodsName":"book"}`) },
+			leaked: []string{"a@b.tw"},
+			kept:   []string{"book"},
+		},
+		{
+			name:   "mobile",
+			log:    func(l *slog.Logger) { l.Info("call 0912-345-678 or +886912345678") },
+			leaked: []string{"0912-345-678", "912345678"},
+		},
+		{
+			name:   "landline with dash",
+			log:    func(l *slog.Logger) { l.Info("call 02-2345-6789 or (04)2345-6789") },
+			leaked: []string{"2345-6789"},
+		},
+		{
+			name:   "landline without dash",
+			log:    func(l *slog.Logger) { l.Info("call 0223456789 or 037123456") },
+			leaked: []string{"0223456789", "037123456"},
+		},
+		{
+			name:   "card with separators",
+			log:    func(l *slog.Logger) { l.Info("card 4311-9522-2222-2222 or 4311 9522 2222 2222") },
+			leaked: []string{"4311-9522-2222-2222", "4311 9522 2222 2222"},
+		},
+		{
+			name:   "card without separators",
+			log:    func(l *slog.Logger) { l.Info("card 4311952222222222") },
+			leaked: []string{"4311952222222222"},
+		},
+		{
+			name:   "masked card",
+			log:    func(l *slog.Logger) { l.Info("card 431195******2222") },
+			leaked: []string{"431195******2222"},
+		},
+		{
+			name: "trade number is not a card",
+			log:  func(l *slog.Logger) { l.Info("paid", "TradeNo", "2303121530280427") },
+			kept: []string{"2303121530280427"},
+		},
+		{
+			name:   "email",
+			log:    func(l *slog.Logger) { l.Info("mail buyer@example.com") },
+			leaked: []string{"buyer@example.com"},
+		},
+		{
+			name:   "address",
+			log:    func(l *slog.Logger) { l.Info("ship to 台北市南港區三重路19-2號") },
+			leaked: []string{"三重路19-2號"},
+		},
+		{
+			name:   "attrs added with With",
+			log:    func(l *slog.Logger) { l.With("SenderPhone", "0223456789").Info("send") },
+			leaked: []string{"0223456789"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newTestClient(&buf).Log())
+			out := buf.String()
+
+			for _, s := range tt.leaked {
+				if strings.Contains(out, s) {
+					t.Errorf("output leaks %q: %s", s, out)
+				}
+			}
+			for _, s := range tt.kept {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lost %q: %s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestLogReusesRedactingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(&buf)
+
+	if c.Log() != c.Log() {
+		t.Error("Log() built a new logger for an unchanged client")
+	}
+
+	// ECPayClient must stay safe to copy by value
+	copied := *c
+	if copied.Log() != c.Log() {
+		t.Error("a copied client did not share the redacting logger")
+	}
+
+	copied.HashKey = "another-key"
+	if copied.Log() == c.Log() {
+		t.Error("Log() reused the logger after HashKey changed")
+	}
+}
+
+func TestLogWithoutLogger(t *testing.T) {
+	var c *ECPayClient
+	if c.Log() != DiscardLogger() {
+		t.Error("nil client should log to DiscardLogger")
+	}
+	if (&ECPayClient{}).SigningLog() != DiscardLogger() {
+		t.Error("SigningLog should discard unless TraceSigning is set")
+	}
+}
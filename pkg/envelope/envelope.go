@@ -0,0 +1,138 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"strconv"
+	"time"
+)
+
+// Request is the JSON envelope of ECPay's AES APIs (全方位物流, 電子發票, 站內付 2.0).
+// Data carries the AES-encrypted business payload.
+type Request struct {
+	MerchantID string         `json:"MerchantID"`
+	RqHeader   model.RqHeader `json:"RqHeader"`
+	Data       string         `json:"Data"`
+}
+
+// Response is the envelope ECPay answers with.
+type Response struct {
+	MerchantID string          `json:"MerchantID"`
+	RpHeader   json.RawMessage `json:"RpHeader,omitempty"`
+	TransCode  int             `json:"TransCode"`
+	TransMsg   string          `json:"TransMsg"`
+	Data       string          `json:"Data"`
+}
+
+// TransError reports a transport-level failure (TransCode != 1): the envelope was
+// rejected before the business logic ran, e.g. a decryption or format error.
+type TransError struct {
+	Code int
+	Msg  string
+}
+
+func (e *TransError) Error() string {
+	return fmt.Sprintf("ECPay TransCode %d: %s", e.Code, e.Msg)
+}
+
+// ResultError reports a business failure (RtnCode != 1) inside a successfully delivered envelope.
+type ResultError struct {
+	Code int
+	Msg  string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s (RtnCode %d)", e.Msg, e.Code)
+}
+
+// Option customises the request header.
+type Option func(*model.RqHeader)
+
+// WithRevision sets RqHeader.Revision, required by the e-invoice APIs.
+func WithRevision(revision string) Option {
+	return func(h *model.RqHeader) {
+		h.Revision = revision
+	}
+}
+
+// Encode encrypts payload with the client's HashKey/HashIV and wraps it in the envelope.
+// An empty merchantID falls back to the client's MerchantID.
+func Encode(c *client.ECPayClient, merchantID string, payload any, opts ...Option) ([]byte, error) {
+
+	if merchantID == "" {
+		merchantID = c.MerchantID
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request data: %w", err)
+	}
+
+	encryptedData, err := helpers.EncryptData(string(data), c.HashKey, c.HashIV)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting request data: %w", err)
+	}
+
+	header := model.RqHeader{Timestamp: strconv.FormatInt(time.Now().Unix(), 10)}
+	for _, opt := range opts {
+		opt(&header)
+	}
+
+	return json.Marshal(Request{MerchantID: merchantID, RqHeader: header, Data: encryptedData})
+}
+
+// Decode unwraps an envelope, decrypts its Data and unmarshals it into a new Resp.
+// A *TransError is returned when TransCode is not 1. When the payload carries an
+// RtnCode other than 1, the decoded Resp is returned together with a *ResultError.
+func Decode[Resp any](c *client.ECPayClient, body []byte) (*Resp, error) {
+
+	envelope := Response{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("error decoding response envelope: %w", err)
+	}
+	c.Log().Debug("ECPay envelope received", "TransCode", envelope.TransCode, "TransMsg", envelope.TransMsg)
+
+	if envelope.TransCode != 1 {
+		return nil, &TransError{Code: envelope.TransCode, Msg: envelope.TransMsg}
+	}
+
+	decryptedData, err := helpers.DecryptData(envelope.Data, c.HashKey, c.HashIV)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting response data: %w", err)
+	}
+
+	resp := new(Resp)
+	if err = json.Unmarshal([]byte(decryptedData), resp); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	result := struct {
+		RtnCode *int   `json:"RtnCode"`
+		RtnMsg  string `json:"RtnMsg"`
+	}{}
+	if err = json.Unmarshal([]byte(decryptedData), &result); err == nil && result.RtnCode != nil && *result.RtnCode != 1 {
+		return resp, &ResultError{Code: *result.RtnCode, Msg: result.RtnMsg}
+	}
+
+	return resp, nil
+}
+
+// Call sends req to api inside an encrypted envelope and decodes the reply into Resp.
+func Call[Req any, Resp any](ctx context.Context, c *client.ECPayClient, api client.API, merchantID string, req Req, opts ...Option) (*Resp, error) {
+
+	payload, err := Encode(c, merchantID, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := helpers.SendJSONContext(ctx, c, api, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode[Resp](c, body)
+}
@@ -0,0 +1,178 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type payload struct {
+	RtnCode int    `json:"RtnCode,omitempty"`
+	RtnMsg  string `json:"RtnMsg,omitempty"`
+	TradeNo string `json:"TradeNo,omitempty"`
+}
+
+func testClient() *client.ECPayClient {
+	return client.StagePayment.Client(client.Stage)
+}
+
+// response builds the envelope ECPay answers with, encrypting data with the client's keys.
+func response(t *testing.T, c *client.ECPayClient, transCode int, data string) []byte {
+	t.Helper()
+
+	encrypted := ""
+	if data != "" {
+		var err error
+		if encrypted, err = helpers.EncryptData(data, c.HashKey, c.HashIV); err != nil {
+			t.Fatalf("EncryptData() error = %v", err)
+		}
+	}
+	body, err := json.Marshal(Response{MerchantID: c.MerchantID, TransCode: transCode, TransMsg: "msg", Data: encrypted})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return body
+}
+
+func TestDecode(t *testing.T) {
+	c := testClient()
+
+	tests := []struct {
+		name          string
+		body          []byte
+		wantTrans     *TransError
+		wantResult    *ResultError
+		wantErr       bool
+		wantTradeNo   string
+		wantNilResult bool
+	}{
+		{
+			name:        "success",
+			body:        response(t, c, 1, `{"RtnCode":1,"RtnMsg":"成功","TradeNo":"T1"}`),
+			wantTradeNo: "T1",
+		},
+		{
+			name:        "payload without RtnCode",
+			body:        response(t, c, 1, `{"TradeNo":"T1"}`),
+			wantTradeNo: "T1",
+		},
+		{
+			name:          "transport failure",
+			body:          response(t, c, 999, ""),
+			wantTrans:     &TransError{Code: 999, Msg: "msg"},
+			wantNilResult: true,
+		},
+		{
+			name:        "business failure",
+			body:        response(t, c, 1, `{"RtnCode":10100050,"RtnMsg":"參數錯誤","TradeNo":"T1"}`),
+			wantResult:  &ResultError{Code: 10100050, Msg: "參數錯誤"},
+			wantTradeNo: "T1",
+		},
+		{name: "not json", body: []byte("<html>"), wantErr: true, wantNilResult: true},
+		{name: "undecryptable data", body: []byte(`{"TransCode":1,"Data":"not-base64!"}`), wantErr: true, wantNilResult: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Decode[payload](c, tt.body)
+
+			var transErr *TransError
+			var resultErr *ResultError
+			switch {
+			case tt.wantTrans != nil:
+				if !errors.As(err, &transErr) || *transErr != *tt.wantTrans {
+					t.Errorf("Decode() error = %v, want %v", err, tt.wantTrans)
+				}
+				if errors.As(err, &resultErr) {
+					t.Error("a transport failure is not a ResultError")
+				}
+			case tt.wantResult != nil:
+				if !errors.As(err, &resultErr) || *resultErr != *tt.wantResult {
+					t.Errorf("Decode() error = %v, want %v", err, tt.wantResult)
+				}
+				if errors.As(err, &transErr) {
+					t.Error("a business failure is not a TransError")
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &transErr) || errors.As(err, &resultErr) {
+					t.Errorf("Decode() error = %v, want a decoding error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+			}
+
+			if tt.wantNilResult {
+				if resp != nil {
+					t.Errorf("Decode() returned %+v, want nil", resp)
+				}
+				return
+			}
+			if resp == nil || resp.TradeNo != tt.wantTradeNo {
+				t.Errorf("Decode() = %+v, want TradeNo %s", resp, tt.wantTradeNo)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	c := testClient()
+
+	body, err := Encode(c, "", payload{TradeNo: "T1"}, WithRevision("3.0.0"))
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	request := Request{}
+	if err = json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if request.MerchantID != c.MerchantID {
+		t.Errorf("MerchantID = %q, want the client's %q", request.MerchantID, c.MerchantID)
+	}
+	if request.RqHeader.Revision != "3.0.0" || request.RqHeader.Timestamp == "" {
+		t.Errorf("RqHeader = %+v", request.RqHeader)
+	}
+
+	data, err := helpers.DecryptData(request.Data, c.HashKey, c.HashIV)
+	if err != nil {
+		t.Fatalf("DecryptData() error = %v", err)
+	}
+	if data != `{"TradeNo":"T1"}` {
+		t.Errorf("Data = %s", data)
+	}
+
+	if body, _ = Encode(c, "2000132", payload{}); !strings.Contains(string(body), `"MerchantID":"2000132"`) {
+		t.Errorf("explicit MerchantID not used: %s", body)
+	}
+}
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCallResultError(t *testing.T) {
+	c := testClient()
+	reply := response(t, c, 1, `{"RtnCode":0,"RtnMsg":"失敗"}`)
+	c.HTTPClient = doerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", req.Header.Get("Content-Type"))
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(reply)))}, nil
+	})
+
+	resp, err := Call[payload, payload](context.Background(), c, client.APIQueryTradeInfo, "", payload{TradeNo: "T1"})
+	var resultErr *ResultError
+	if !errors.As(err, &resultErr) || resultErr.Code != 0 || resp == nil {
+		t.Errorf("Call() = %+v, %v; want the payload and a ResultError", resp, err)
+	}
+}
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+var autoSubmitFormTemplate = template.Must(template.New("form").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>ECPay</title></head>
+<body>
+<form id="{{.ID}}" method="post" action="{{.Action}}" accept-charset="UTF-8">
+{{- range .Fields}}
+<input type="hidden" name="{{.Name}}" value="{{.Value}}">
+{{- end}}
+<noscript><button type="submit">{{.Label}}</button></noscript>
+</form>
+<script>document.getElementById("{{.ID}}").submit();</script>
+</body>
+</html>
+`))
+
+type formField struct {
+	Name  string
+	Value string
+}
+
+// AutoSubmitFormHTML renders an HTML page whose form, identified by id, posts values to
+// action as soon as it loads. label is shown on the submit button when JavaScript is off.
+// Every name and value is HTML-escaped and the fields are written in key order.
+func AutoSubmitFormHTML(id, label, action string, values url.Values) (string, error) {
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]formField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, formField{Name: key, Value: values.Get(key)})
+	}
+
+	var buf bytes.Buffer
+	data := struct {
+		ID     string
+		Label  string
+		Action string
+		Fields []formField
+	}{ID: id, Label: label, Action: action, Fields: fields}
+	if err := autoSubmitFormTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// ServeAutoSubmitForm writes the page of AutoSubmitFormHTML, sending the buyer's browser to action.
+func ServeAutoSubmitForm(w http.ResponseWriter, id, label, action string, values url.Values) {
+
+	page, err := AutoSubmitFormHTML(id, label, action, values)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(page))
+}
@@ -1,8 +1,11 @@
 package helpers
 
 import (
+	"bytes"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/md5"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -79,8 +82,7 @@ func reflectStruct(v reflect.Value, values *url.Values) {
 				values.Set(tag, fmt.Sprintf("%v", field.Elem().Interface()))
 			}
 		default:
-			// 處理不支持的類型
-			slog.Error("Unsupported type: %v\n", field.Kind())
+			// 不支持的類型不列入表單
 		}
 	}
 }
@@ -92,6 +94,87 @@ func isTimeStruct(v reflect.Value) bool {
 	return false
 }
 
+// BindFormValues fills the form-tagged fields of dst (a pointer to struct) from values.
+// It is the reverse of ReflectFormValues; missing keys leave fields untouched.
+func BindFormValues(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("dst must be a non-nil pointer to struct")
+	}
+	return bindStruct(v.Elem(), values)
+}
+
+func bindStruct(v reflect.Value, values url.Values) error {
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		fieldType := v.Type().Field(i)
+		tag := fieldType.Tag.Get("form")
+
+		if tag == "" {
+			if field.Kind() == reflect.Struct && !isTimeStruct(field) {
+				if err := bindStruct(field, values); err != nil { // 遞迴處理嵌套結構體
+					return err
+				}
+			}
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+		if _, ok := values[tag]; !ok {
+			continue
+		}
+		strVal := values.Get(tag)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(strVal)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if strVal == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(strVal, 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", tag, err)
+			}
+			field.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if strVal == "" {
+				continue
+			}
+			n, err := strconv.ParseUint(strVal, 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", tag, err)
+			}
+			field.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			if strVal == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(strVal, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", tag, err)
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			if strVal == "" {
+				continue
+			}
+			b, err := strconv.ParseBool(strVal)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", tag, err)
+			}
+			field.SetBool(b)
+		default:
+			// 不支持的類型略過
+		}
+	}
+
+	return nil
+}
+
 // EncryptData 使用 ECPay 的加密方式對數據進行加密
 func EncryptData(data string, hashKey string, hashIV string) (string, error) {
 	// URL 編碼
@@ -159,11 +242,53 @@ func DecryptData(encryptData, hashKey, hashIV string) (string, error) {
 	return result, nil
 }
 
+// HashAlgorithm CheckMacValue 雜湊演算法, 數值與 EncryptType 參數相同
+type HashAlgorithm int
+
+const (
+	// HashMD5 EncryptType=0, 物流 API 與舊版金流使用
+	HashMD5 HashAlgorithm = 0
+
+	// HashSHA256 EncryptType=1, 全方位金流使用
+	HashSHA256 HashAlgorithm = 1
+)
+
+// CheckMacOption configures GenerateCheckMacValue.
+type CheckMacOption func(*checkMacConfig)
+
+type checkMacConfig struct {
+	logger    *slog.Logger
+	algorithm HashAlgorithm
+}
+
+// WithHashAlgorithm selects the hash algorithm, SHA256 by default.
+func WithHashAlgorithm(algorithm HashAlgorithm) CheckMacOption {
+	return func(c *checkMacConfig) {
+		c.algorithm = algorithm
+	}
+}
+
+// WithLogger traces every signing step at debug level to logger.
+// Pass ECPayClient.SigningLog() so that HashKey/HashIV are redacted.
+func WithLogger(logger *slog.Logger) CheckMacOption {
+	return func(c *checkMacConfig) {
+		c.logger = logger
+	}
+}
+
 // GenerateCheckMacValue generates CheckMacValue
-func GenerateCheckMacValue(values url.Values, hashKey string, hashIV string) string {
+func GenerateCheckMacValue(values url.Values, hashKey string, hashIV string, opts ...CheckMacOption) string {
+
+	config := checkMacConfig{algorithm: HashSHA256}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	logger := config.logger
+	if logger == nil {
+		logger = client.DiscardLogger()
+	}
 
 	// Step (1) 將傳遞參數依照第一個英文字母，由A到Z的順序來排序
-	slog.Info(fmt.Sprintf("Step (1) values: %v", values))
 	keys := make([]string, 0, len(values))
 	for key := range values {
 		keys = append(keys, key)
@@ -176,19 +301,24 @@ func GenerateCheckMacValue(values url.Values, hashKey string, hashIV string) str
 	}
 	// remove trailing '&'
 	sortedQueryString = strings.TrimSuffix(sortedQueryString, "&")
-	slog.Info(fmt.Sprintf("Step (1) sortedQueryString: %v", sortedQueryString))
+	logger.Debug("CheckMacValue step (1)", "sortedQueryString", sortedQueryString)
 
 	// Step (2) 參數最前面加上HashKey、最後面加上HashIV
 	encodedString := "HashKey=" + hashKey + "&" + sortedQueryString + "&HashIV=" + hashIV
-	slog.Info(fmt.Sprintf("Step (2) encodedString: %v", encodedString))
-	// Step (3) 將整串字串進行URL encode
-	encodedString = url.QueryEscape(encodedString)
+	logger.Debug("CheckMacValue step (2)", "encodedString", encodedString)
+
+	// Step (3) 將整串字串進行URL encode (依 .NET 編碼規則)
+	encodedString = DotNetURLEncode(encodedString)
 
 	// Step (4) 轉為小寫
 	encodedString = strings.ToLower(encodedString)
+	logger.Debug("CheckMacValue step (4)", "encodedString", encodedString)
 
-	// Step (5) 以SHA256加密方式來產生雜凑值
+	// Step (5) 以SHA256 (EncryptType=1) 或 MD5 (EncryptType=0) 加密方式來產生雜凑值
 	hasher := sha256.New()
+	if config.algorithm == HashMD5 {
+		hasher = md5.New()
+	}
 	hasher.Write([]byte(encodedString))
 	hashedValue := hex.EncodeToString(hasher.Sum(nil))
 
@@ -196,22 +326,132 @@ func GenerateCheckMacValue(values url.Values, hashKey string, hashIV string) str
 	return strings.ToUpper(hashedValue)
 }
 
+// DotNetURLEncode encodes s the way .NET HttpUtility.UrlEncode does, which is what
+// ECPay uses to compute CheckMacValue: letters, digits and "-_.!*()" are kept,
+// space becomes "+" and every other byte is percent-encoded with lowercase hex.
+// This differs from url.QueryEscape, which escapes "!*()" and keeps "~".
+func DotNetURLEncode(s string) string {
+	const hexDigits = "0123456789abcdef"
+
+	var sb strings.Builder
+	sb.Grow(len(s) * 3)
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+			sb.WriteByte(b)
+		case b == '-', b == '_', b == '.', b == '!', b == '*', b == '(', b == ')':
+			sb.WriteByte(b)
+		case b == ' ':
+			sb.WriteByte('+')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexDigits[b>>4])
+			sb.WriteByte(hexDigits[b&0x0f])
+		}
+	}
+	return sb.String()
+}
+
+// SendFormData sends formData as an application/x-www-form-urlencoded POST to the client's
+// BaseURL or, without one, to the AioCheckOut endpoint of its Environment.
+//
+// Deprecated: use SendFormDataContext, which names the API and carries a context.
 func SendFormData(c *client.ECPayClient, formData url.Values) ([]byte, error) {
+	return SendFormDataContext(context.Background(), c, client.APIAioCheckOut, formData)
+}
+
+// SendFormDataContext posts formData to the endpoint of api resolved by the client,
+// carrying ctx so cancellation and deadlines reach ECPay.
+func SendFormDataContext(ctx context.Context, c *client.ECPayClient, api client.API, formData url.Values) ([]byte, error) {
+	endpoint, err := c.URL(api)
+	if err != nil {
+		return nil, err
+	}
+	return send(ctx, c, endpoint, "application/x-www-form-urlencoded", strings.NewReader(formData.Encode()))
+}
+
+// SendJSONContext posts the JSON payload to the endpoint of api and returns the raw response body.
+func SendJSONContext(ctx context.Context, c *client.ECPayClient, api client.API, payload []byte) ([]byte, error) {
+	endpoint, err := c.URL(api)
+	if err != nil {
+		return nil, err
+	}
+	return send(ctx, c, endpoint, "application/json", bytes.NewReader(payload))
+}
+
+// OpenFormDataContext posts formData to the endpoint of api and returns the response body
+// unread, for downloads that should be streamed. The caller must close it.
+// The request is sent with c.DownloadDoer(), bounded by client.DefaultDownloadTimeout and ctx.
+func OpenFormDataContext(ctx context.Context, c *client.ECPayClient, api client.API, formData url.Values) (io.ReadCloser, error) {
+
+	endpoint, err := c.URL(api)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := http.PostForm(c.BaseURL, formData)
+	resp, err := do(ctx, c, c.DownloadDoer(), endpoint, "application/x-www-form-urlencoded", strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, statusError(resp, snippet)
+	}
+
+	return resp.Body, nil
+}
+
+// maxErrorBody 錯誤訊息中保留的回應內容長度上限
+const maxErrorBody = 512
+
+// statusError reports a non-2xx response, quoting the start of its body.
+func statusError(resp *http.Response, body []byte) error {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	return fmt.Errorf("unexpected response status: %s: %q", resp.Status, strings.TrimSpace(string(body)))
+}
+
+func do(ctx context.Context, c *client.ECPayClient, doer client.Doer, endpoint string, contentType string, body io.Reader) (*http.Response, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating POST request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.Log().Debug("Sending request to ECPay", "url", endpoint)
+
+	resp, err := doer.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error sending POST request: %w", err)
 	}
+
+	return resp, nil
+}
+
+func send(ctx context.Context, c *client.ECPayClient, endpoint string, contentType string, body io.Reader) ([]byte, error) {
+
+	resp, err := do(ctx, c, c.Doer(), endpoint, contentType, body)
+	if err != nil {
+		return nil, err
+	}
 	defer func(Body io.ReadCloser) {
 		if err = Body.Close(); err != nil {
-			slog.Error(fmt.Sprintf("Error closing response body: %v", err))
+			c.Log().Error(fmt.Sprintf("Error closing response body: %v", err))
 		}
 	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
-	return body, nil
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, statusError(resp, respBody)
+	}
+
+	return respBody, nil
 }
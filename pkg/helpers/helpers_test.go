@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDotNetURLEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "alphanumeric", in: "AaZz09", want: "AaZz09"},
+		{name: "kept punctuation", in: "()!*-_.", want: "()!*-_."},
+		{name: "tilde and quote", in: "~'", want: "%7e%27"},
+		{name: "space", in: "Apple iphone 15", want: "Apple+iphone+15"},
+		{name: "reserved", in: "a=b&c/d:e", want: "a%3db%26c%2fd%3ae"},
+		{name: "cjk", in: "促銷方案", want: "%e4%bf%83%e9%8a%b7%e6%96%b9%e6%a1%88"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DotNetURLEncode(tt.in); got != tt.want {
+				t.Errorf("DotNetURLEncode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// aioExampleEncoded 技術文件範例步驟 (4): 加上 HashKey/HashIV, URL encode 並轉小寫後的字串
+const aioExampleEncoded = "hashkey%3dpwfhcqoqzgmho4w6%26choosepayment%3dall%26encrypttype%3d1%26itemname%3dapple+iphone+15" +
+	"%26merchantid%3d3002607%26merchanttradedate%3d2023%2f03%2f12+15%3a30%3a23%26merchanttradeno%3decpay20230312153023" +
+	"%26paymenttype%3daio%26returnurl%3dhttps%3a%2f%2fwww.ecpay.com.tw%2freceive.php%26totalamount%3d30000" +
+	"%26tradedesc%3d%e4%bf%83%e9%8a%b7%e6%96%b9%e6%a1%88%26hashiv%3dekrm7ift261dpevs"
+
+// aioExample 全方位金流技術文件 CheckMacValue 範例的參數
+var aioExample = url.Values{
+	"ChoosePayment":     {"ALL"},
+	"EncryptType":       {"1"},
+	"ItemName":          {"Apple iphone 15"},
+	"MerchantID":        {"3002607"},
+	"MerchantTradeDate": {"2023/03/12 15:30:23"},
+	"MerchantTradeNo":   {"ecpay20230312153023"},
+	"PaymentType":       {"aio"},
+	"ReturnURL":         {"https://www.ecpay.com.tw/receive.php"},
+	"TotalAmount":       {"30000"},
+	"TradeDesc":         {"促銷方案"},
+}
+
+func TestGenerateCheckMacValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  url.Values
+		hashKey string
+		hashIV  string
+		opts    []CheckMacOption
+		want    string
+	}{
+		{
+			// 技術文件公布的檢查碼
+			name:    "aio sha256",
+			values:  aioExample,
+			hashKey: "pwFHCqoQZGmho4w6",
+			hashIV:  "EkRm7iFT261dpevs",
+			want:    "6C51C9E6888DE861FD62FB1DD17029FC742634498FD813DC43D4243B5685B840",
+		},
+		{
+			// 同一組文件參數改以 MD5 (EncryptType=0, 物流 API) 雜湊; 預期值為 aioExampleEncoded
+			// 取 MD5 後轉大寫, 見 TestDocumentedEncodedString
+			name:    "documented example md5",
+			values:  aioExample,
+			hashKey: "pwFHCqoQZGmho4w6",
+			hashIV:  "EkRm7iFT261dpevs",
+			opts:    []CheckMacOption{WithHashAlgorithm(HashMD5)},
+			want:    "59D592BDBEEFEB697713106D818DD292",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateCheckMacValue(tt.values, tt.hashKey, tt.hashIV, tt.opts...); got != tt.want {
+				t.Errorf("GenerateCheckMacValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDocumentedEncodedString ties the MD5 vector to the documentation: the step (4) string
+// hashes to the published SHA256 CheckMacValue, and the MD5 vector is the MD5 of that string.
+func TestDocumentedEncodedString(t *testing.T) {
+	sum := sha256.Sum256([]byte(aioExampleEncoded))
+	if got := strings.ToUpper(hex.EncodeToString(sum[:])); got != "6C51C9E6888DE861FD62FB1DD17029FC742634498FD813DC43D4243B5685B840" {
+		t.Fatalf("step (4) string does not hash to the published value: %s", got)
+	}
+
+	md5Sum := md5.Sum([]byte(aioExampleEncoded))
+	if got := strings.ToUpper(hex.EncodeToString(md5Sum[:])); got != "59D592BDBEEFEB697713106D818DD292" {
+		t.Errorf("MD5 of the step (4) string = %s", got)
+	}
+}
@@ -1,62 +1,40 @@
 package logistics
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
-	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
-	"io"
-	"log/slog"
-	"net/http"
-	"strconv"
-	"strings"
-	"time"
+	"context"
 )
 
-// CreateTestData is a method that creates test data for the ECPayLogistics struct and sends it to the ECPayClient server for processing and decryption. The method returns the decrypted
+// CreateTestData 產生 B2C 測試標籤資料. The result is a copy of e carrying the
+// response fields; e itself is left untouched.
 func (e *ECPayLogistics) CreateTestData() (*ECPayLogistics, error) {
+	return e.CreateTestDataContext(context.Background())
+}
 
-	if err := e.EncryptLogistics(); err != nil {
-		return nil, err
-	}
+// CreateTestDataContext is like CreateTestData but carries ctx to the outgoing request.
+func (e *ECPayLogistics) CreateTestDataContext(ctx context.Context) (*ECPayLogistics, error) {
 
-	e.RqHeader = model.RqHeader{
-		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
-	}
-
-	jsonData, err := json.Marshal(e)
-	if err != nil {
-		slog.Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
-		return nil, err
+	request := &TestDataRequest{
+		Client:           e.Client,
+		MerchantID:       e.MerchantID,
+		LogisticsSubType: e.LogisticsSubType,
+		ClientReplyURL:   e.ClientReplyURL,
 	}
 
-	resp, err := http.Post(e.Client.BaseURL, "application/json", strings.NewReader(string(jsonData)))
+	order, err := request.CreateTestDataContext(ctx)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Error sending POST request: %v", err))
-		return nil, err
-	}
-
-	defer func(Body io.ReadCloser) {
-		if err = Body.Close(); err != nil {
-			slog.Error(err.Error())
-		}
-	}(resp.Body)
-
-	responseData := ECPayLogistics{}
-	if err = json.NewDecoder(resp.Body).Decode(&responseData); err != nil {
-		slog.Error(fmt.Sprintf("Error decoding response body: %v", err))
-		return nil, err
-	}
-
-	slog.Info(fmt.Sprintf("responseData.TransCode %d", responseData.TransCode))
-	slog.Info(fmt.Sprintf("responseData.TransMsg %d", responseData.TransMsg))
-	decryptedData := &ECPayLogistics{}
-	decryptedDataString, err := helpers.DecryptData(responseData.Data, e.Client.HashKey, e.Client.HashIV)
-	if err = json.NewDecoder(bytes.NewReader([]byte(decryptedDataString))).Decode(&decryptedData); err != nil {
-		slog.Error(fmt.Sprintf("Error decoding decrypted data: %v", err))
+		e.Client.Log().Error("Error creating test data", "error", err)
 		return nil, err
 	}
 
-	return decryptedData, nil
+	result := *e
+	result.RtnCode = order.RtnCode
+	result.RtnMsg = order.RtnMsg
+	result.LogisticsID = order.LogisticsID
+	result.MerchantTradeNo = order.MerchantTradeNo
+	result.LogisticsStatus = order.LogisticsStatus
+	result.LogisticsStatusName = order.LogisticsStatusName
+	result.CVSPaymentNo = order.CVSPaymentNo
+	result.CVSValidationNo = order.CVSValidationNo
+	result.BookingNote = order.BookingNote
+	return &result, nil
 }
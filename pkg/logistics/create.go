@@ -0,0 +1,94 @@
+package logistics
+
+import (
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MerchantTradeDateFormat 廠商交易時間格式
+const MerchantTradeDateFormat = "2006/01/02 15:04:05"
+
+// CreateResult is the order ECPay returns from Express/Create
+type CreateResult struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// RtnCode 目前物流狀態
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 物流狀態說明
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID,omitempty" form:"AllPayLogisticsID"`
+
+	// LogisticsType 物流類型
+	LogisticsType string `json:"LogisticsType,omitempty" form:"LogisticsType"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType,omitempty" form:"LogisticsSubType"`
+
+	// GoodsAmount 商品金額
+	GoodsAmount int `json:"GoodsAmount,omitempty" form:"GoodsAmount"`
+
+	// UpdateStatusDate 物流狀態更新時間
+	UpdateStatusDate string `json:"UpdateStatusDate,omitempty" form:"UpdateStatusDate"`
+
+	// Receiver 收件人資訊
+	model.Receiver `json:",inline"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo,omitempty" form:"CVSPaymentNo"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty" form:"CVSValidationNo"`
+
+	// BookingNote 托運單號
+	BookingNote string `json:"BookingNote,omitempty" form:"BookingNote"`
+}
+
+// createOrder signs request, posts it to Express/Create and parses the
+// "1|key=value&..." reply, verifying its CheckMacValue.
+func createOrder(ctx context.Context, c *client.ECPayClient, request any) (*CreateResult, error) {
+
+	body, err := sendSigned(ctx, c, client.APILogisticsCreate, request)
+	if err != nil {
+		return nil, err
+	}
+
+	code, message, _ := strings.Cut(strings.TrimSpace(string(body)), "|")
+	if code != "1" {
+		return nil, fmt.Errorf("建立物流訂單失敗 失敗原因 : %s", message)
+	}
+
+	values, err := url.ParseQuery(message)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing create response: %w", err)
+	}
+	if err = validation.ValidateCheckMacValue(values, c.HashKey, c.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+		return nil, err
+	}
+
+	result := &CreateResult{}
+	if err = helpers.BindFormValues(values, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// tradeDate returns date, or the current time in MerchantTradeDateFormat when it is empty.
+func tradeDate(date string) string {
+	if date != "" {
+		return date
+	}
+	return time.Now().Format(MerchantTradeDateFormat)
+}
@@ -1,19 +1,15 @@
 package logistics
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/envelope"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
-	"io"
-	"log/slog"
-	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
-	"time"
 )
 
 // ECPayLogistics is a struct containing information for an ECPay logistics
@@ -83,23 +79,25 @@ type ECPayLogistics struct {
 	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
 
 	// ScheduledPickupTime 預定取件時段
-	ScheduledPickupTime string `json:"ScheduledPickupTime,omitempty" json:"ScheduledPickupTime"`
+	ScheduledPickupTime string `json:"ScheduledPickupTime,omitempty" form:"ScheduledPickupTime"`
 
 	// EnableSelectDeliveryTime 是否允許選擇送達時間
 	EnableSelectDeliveryTime string `json:"EnableSelectDeliveryTime,omitempty" form:"EnableSelectDeliveryTime"`
 
-	// RqHeader
-	RqHeader model.RqHeader `json:"RqHeader"`
+	// RqHeader 已加密請求的信封標頭, 不會放入加密資料內
+	RqHeader model.RqHeader `json:"-"`
 
-	TransCode int `json:"TransCode"`
+	// TransCode 信封傳輸代碼
+	TransCode int `json:"-"`
 
-	TransMsg string `json:"TransMsg"`
+	// TransMsg 信封傳輸訊息
+	TransMsg string `json:"-"`
 
-	// Data
-	Data string `json:"Data"`
+	// Data 加密後的資料
+	Data string `json:"-"`
 
 	// ResultData
-	ResultData string `json:"ResultData"`
+	ResultData string `json:"-"`
 
 	// BaseModel 通用參數
 	model.BaseModel `json:",inline"`
@@ -121,11 +119,18 @@ type ECPayLogistics struct {
 }
 
 // Map is a function that maps the ECPayLogistics struct to the ECPayClient struct
+//
+// Deprecated: the store picker must run in the buyer's browser; use StoreMapRequest().Form().
 func (e *ECPayLogistics) Map() (string, error) {
+	return e.MapContext(context.Background())
+}
+
+// MapContext is like Map but carries ctx to the outgoing request.
+func (e *ECPayLogistics) MapContext(ctx context.Context) (string, error) {
 
 	formData := helpers.ReflectFormValues(e)
 
-	body, err := helpers.SendFormData(e.Client, formData)
+	body, err := helpers.SendFormDataContext(ctx, e.Client, client.APILogisticsMap, formData)
 	if err != nil {
 		return "", err
 	}
@@ -133,40 +138,77 @@ func (e *ECPayLogistics) Map() (string, error) {
 	return string(body), nil
 }
 
+// StoreMapRequest returns the 電子地圖 request built from e, to be rendered with Form.
+func (e *ECPayLogistics) StoreMapRequest() *StoreMapRequest {
+	return &StoreMapRequest{
+		Client:           e.Client,
+		Merchant:         e.Merchant,
+		LogisticsType:    e.LogisticsType,
+		LogisticsSubType: e.LogisticsSubType,
+		IsCollection:     e.IsCollection,
+		ServerReplyURL:   e.ServerReplyURL,
+		ExtraData:        e.ExtraData,
+	}
+}
+
+// HomeOrderRequest returns the home delivery (TCAT / POST) order built from e.
+func (e *ECPayLogistics) HomeOrderRequest() *HomeOrderRequest {
+	return &HomeOrderRequest{
+		Client:              e.Client,
+		Merchant:            e.Merchant,
+		LogisticsSubType:    e.LogisticsSubType,
+		Goods:               e.Goods,
+		Sender:              e.Sender,
+		Receiver:            e.Receiver,
+		Temperature:         e.Temperature,
+		Specification:       e.Specification,
+		ScheduledPickupTime: e.ScheduledPickupTime,
+		TradeDesc:           e.TradeDesc,
+		ServerReplyURL:      e.ServerReplyURL,
+		Remark:              e.Remark,
+		PlatformID:          e.PlatformID,
+	}
+}
+
 // CreateExpress 綠界物流門市訂單建立
 func (e *ECPayLogistics) CreateExpress() error {
+	return e.CreateExpressContext(context.Background())
+}
 
-	formData := helpers.ReflectFormValues(e)
-
-	checkMacValue := helpers.GenerateCheckMacValue(formData, e.Client.HashKey, e.Client.HashIV)
-	formData.Set("CheckMacValue", checkMacValue)
+// CreateExpressContext is like CreateExpress but carries ctx to the outgoing request.
+// The verified "1|..." reply is copied back into e (RtnCode, AllPayLogisticsID, BookingNote...).
+func (e *ECPayLogistics) CreateExpressContext(ctx context.Context) error {
 
-	body, err := helpers.SendFormData(e.Client, formData)
+	result, err := createOrder(ctx, e.Client, e)
 	if err != nil {
 		return err
 	}
 
-	if err = json.Unmarshal(body, &e); err != nil {
-		return err
-	}
+	e.RtnCode = result.RtnCode
+	e.RtnMsg = result.RtnMsg
+	e.AllPayLogisticsID = result.AllPayLogisticsID
+	e.UpdateStatusDate = result.UpdateStatusDate
+	e.BookingNote = result.BookingNote
 
 	return nil
 }
 
 // EncryptLogistics is a method that encrypts the ECPayLogistics struct using the helpers.EncryptData function and sets the encrypted data to the "Data" field of the struct.
 // It takes no arguments and returns an error if there was an error marshalling the struct or encrypting the data, otherwise it returns nil.
+//
+// Deprecated: use the per-API request types (TempTradeRequest, CreateByTempTradeRequest, ...),
+// which send exactly the fields of the spec.
 func (e *ECPayLogistics) EncryptLogistics() error {
 
 	jsonBytes, err := json.Marshal(e)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
+		e.Client.Log().Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
 		return err
 	}
 
-	jsonString := string(jsonBytes)
-	encryptedData, err := helpers.EncryptData(jsonString, e.Client.HashKey, e.Client.HashIV)
+	encryptedData, err := helpers.EncryptData(string(jsonBytes), e.Client.HashKey, e.Client.HashIV)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Error encrypting data: %v", err))
+		e.Client.Log().Error(fmt.Sprintf("Error encrypting data: %v", err))
 		return err
 	}
 
@@ -174,171 +216,146 @@ func (e *ECPayLogistics) EncryptLogistics() error {
 	return nil
 }
 
+// DecryptLogistics decodes an envelope response body and decrypts its Data into e.
+//
+// Deprecated: use the per-API request types, whose methods return dedicated response structs.
 func (e *ECPayLogistics) DecryptLogistics(body []byte) error {
 
-	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&e); err != nil {
-		slog.Error(fmt.Sprintf("Error decoding response body: %v", err))
+	response := envelope.Response{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		e.Client.Log().Error(fmt.Sprintf("Error decoding response body: %v", err))
 		return err
 	}
+	e.TransCode, e.TransMsg, e.Data = response.TransCode, response.TransMsg, response.Data
 
-	slog.Info(fmt.Sprintf("Body : %s", string(body)))
-	slog.Info(fmt.Sprintf("TransCode : %d", e.TransCode))
-	slog.Info(fmt.Sprintf("TransMsg : %s", e.TransMsg))
-	slog.Info(fmt.Sprintf("Data : %s", e.Data))
+	e.Client.Log().Debug(fmt.Sprintf("TransCode : %d", e.TransCode))
+	e.Client.Log().Debug(fmt.Sprintf("TransMsg : %s", e.TransMsg))
 	decryptedDataString, err := helpers.DecryptData(e.Data, e.Client.HashKey, e.Client.HashIV)
-	if err = json.NewDecoder(bytes.NewReader([]byte(decryptedDataString))).Decode(&e); err != nil {
-		slog.Error(fmt.Sprintf("Error decoding decrypted data: %v", err))
+	if err != nil {
+		e.Client.Log().Error(fmt.Sprintf("Error decrypting data: %v", err))
+		return err
+	}
+	if err = json.Unmarshal([]byte(decryptedDataString), e); err != nil {
+		e.Client.Log().Error(fmt.Sprintf("Error decoding decrypted data: %v", err))
 		return err
 	}
 
 	return nil
 }
 
-func (e *ECPayLogistics) RedirectToLogisticsSelection() (string, error) {
-
-	if err := e.EncryptLogistics(); err != nil {
-		return "", err
+// TempTradeRequest returns the RedirectToLogisticsSelection / UpdateTempTrade payload built from e.
+func (e *ECPayLogistics) TempTradeRequest() *TempTradeRequest {
+	return &TempTradeRequest{
+		Client:          e.Client,
+		MerchantID:      e.MerchantID,
+		TempLogisticsID: e.TempLogisticsID,
+		Goods:           e.Goods,
+		IsCollection:    e.IsCollection,
+		Sender:          e.Sender,
+		Receiver:        e.Receiver,
+		ReturnStoreID:   e.ReturnStoreID,
+		Remark:          e.Remark,
+		ServerReplyURL:  e.ServerReplyURL,
+		ClientReplyURL:  e.ClientReplyURL,
 	}
+}
 
-	e.RqHeader = model.RqHeader{
-		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
-	}
+// RedirectToLogisticsSelection 取得綠界物流選擇頁, 回傳解密後的 RtnMsg.
+// When the reply cannot be decoded, the raw body is returned together with the error.
+// Use LogisticsSelectionHTML for the page itself.
+func (e *ECPayLogistics) RedirectToLogisticsSelection() (string, error) {
+	return e.RedirectToLogisticsSelectionContext(context.Background())
+}
 
-	jsonData, err := json.Marshal(e)
-	if err != nil {
-		slog.Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
-		return "", err
-	}
+// RedirectToLogisticsSelectionContext is like RedirectToLogisticsSelection but carries ctx to the outgoing request.
+func (e *ECPayLogistics) RedirectToLogisticsSelectionContext(ctx context.Context) (string, error) {
 
-	resp, err := http.Post(e.Client.BaseURL, "application/json", strings.NewReader(string(jsonData)))
+	body, err := e.TempTradeRequest().redirectToLogisticsSelection(ctx)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Error sending POST request: %v", err))
 		return "", err
 	}
 
-	defer func(Body io.ReadCloser) {
-		if err = Body.Close(); err != nil {
-			slog.Error(err.Error())
-		}
-	}(resp.Body)
-
-	responseData := &ECPayLogistics{
-		BaseModel: model.BaseModel{
-			Client: &client.ECPayClient{
-				HashKey: e.Client.HashKey,
-				HashIV:  e.Client.HashIV,
-			},
-		},
-	}
-
-	body, _ := io.ReadAll(resp.Body)
-	if err = responseData.DecryptLogistics(body); err != nil {
+	responseData, err := envelope.Decode[TempTradeResponse](e.Client, body)
+	if err != nil {
 		return string(body), err
 	}
 
 	return responseData.RtnMsg, nil
 }
 
-func (e *ECPayLogistics) UpdateTempTrade() error {
-
-	if err := e.EncryptLogistics(); err != nil {
-		return err
-	}
-
-	e.RqHeader = model.RqHeader{
-		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
-	}
-
-	jsonData, err := json.Marshal(e)
-	if err != nil {
-		slog.Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
-		return err
-	}
-
-	resp, err := http.Post(e.Client.BaseURL, "application/json", strings.NewReader(string(jsonData)))
-	if err != nil {
-		slog.Error(fmt.Sprintf("Error sending POST request: %v", err))
-		return err
-	}
+// LogisticsSelectionHTML 取得綠界物流選擇頁, 回傳頁面 HTML
+func (e *ECPayLogistics) LogisticsSelectionHTML() (string, error) {
+	return e.LogisticsSelectionHTMLContext(context.Background())
+}
 
-	defer func(Body io.ReadCloser) {
-		if err = Body.Close(); err != nil {
-			slog.Error(err.Error())
-		}
-	}(resp.Body)
-
-	responseData := &ECPayLogistics{
-		BaseModel: model.BaseModel{
-			Client: &client.ECPayClient{
-				HashKey: e.Client.HashKey,
-				HashIV:  e.Client.HashIV,
-			},
-		},
-	}
+// LogisticsSelectionHTMLContext is like LogisticsSelectionHTML but carries ctx to the outgoing request.
+func (e *ECPayLogistics) LogisticsSelectionHTMLContext(ctx context.Context) (string, error) {
+	return e.TempTradeRequest().RedirectToLogisticsSelectionContext(ctx)
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	if err = responseData.DecryptLogistics(body); err != nil {
-		return err
-	}
+// UpdateTempTrade 更新暫存物流訂單
+func (e *ECPayLogistics) UpdateTempTrade() error {
+	return e.UpdateTempTradeContext(context.Background())
+}
 
-	if responseData.RtnCode != 1 {
-		slog.Info(fmt.Sprintf("responseData.RtnCode : %d", responseData.RtnCode))
-		slog.Info(fmt.Sprintf("responseData.RtnMsg : %s", responseData.RtnMsg))
-		return errors.New(fmt.Sprintf("建立暫存物流訂單失敗 失敗原因 : %s", responseData.RtnMsg))
-	}
+// UpdateTempTradeContext is like UpdateTempTrade but carries ctx to the outgoing request.
+func (e *ECPayLogistics) UpdateTempTradeContext(ctx context.Context) error {
 
-	return nil
+	_, err := e.TempTradeRequest().UpdateTempTradeContext(ctx)
+	return err
 }
 
+// CreateByTempTrade 以暫存物流訂單建立正式物流訂單, 回傳綠界物流訂單編號
 func (e *ECPayLogistics) CreateByTempTrade() (string, error) {
+	return e.CreateByTempTradeContext(context.Background())
+}
 
-	if err := e.EncryptLogistics(); err != nil {
-		return "", err
-	}
+// CreateByTempTradeContext is like CreateByTempTrade but carries ctx to the outgoing request.
+func (e *ECPayLogistics) CreateByTempTradeContext(ctx context.Context) (string, error) {
 
-	e.RqHeader = model.RqHeader{
-		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+	request := &CreateByTempTradeRequest{
+		Client:          e.Client,
+		MerchantID:      e.MerchantID,
+		TempLogisticsID: e.TempLogisticsID,
+		MerchantTradeNo: e.MerchantTradeNo,
 	}
 
-	jsonData, err := json.Marshal(e)
+	order, err := request.CreateByTempTradeContext(ctx)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Error marshalling ECPayLogistics struct: %v", err))
 		return "", err
 	}
 
-	resp, err := http.Post(e.Client.BaseURL, "application/json", strings.NewReader(string(jsonData)))
-	if err != nil {
-		slog.Error(fmt.Sprintf("Error sending POST request: %v", err))
-		return "", err
-	}
+	return order.LogisticsID, nil
+}
 
-	defer func(Body io.ReadCloser) {
-		if err = Body.Close(); err != nil {
-			slog.Error(err.Error())
-		}
-	}(resp.Body)
-
-	responseData := &ECPayLogistics{
-		BaseModel: model.BaseModel{
-			Client: &client.ECPayClient{
-				HashKey: e.Client.HashKey,
-				HashIV:  e.Client.HashIV,
-			},
-		},
-	}
+// signedForm reflects request into form values signed with the MD5 CheckMacValue
+// every logistics form API expects.
+func signedForm(c *client.ECPayClient, request any) url.Values {
 
-	body, _ := io.ReadAll(resp.Body)
-	if err = responseData.DecryptLogistics(body); err != nil {
-		return "", err
-	}
+	formData := helpers.ReflectFormValues(request)
+	formData.Del("CheckMacValue")
+	c.DefaultMerchantID(formData)
 
-	if responseData.RtnCode != 1 {
-		return "", errors.New(fmt.Sprintf("建立正式物流訂單失敗 失敗原因 : %s", responseData.RtnMsg))
-	}
+	checkMacValue := helpers.GenerateCheckMacValue(formData, c.HashKey, c.HashIV,
+		helpers.WithHashAlgorithm(helpers.HashMD5),
+		helpers.WithLogger(c.SigningLog()))
+	formData.Set("CheckMacValue", checkMacValue)
 
-	return responseData.LogisticsID, nil
+	return formData
 }
 
-//func (e *ECPayLogistics)CreateTestData() (string, error) {
-//
-//}
+// sendSigned signs request and posts it to api.
+func sendSigned(ctx context.Context, c *client.ECPayClient, api client.API, request any) ([]byte, error) {
+	return helpers.SendFormDataContext(ctx, c, api, signedForm(c, request))
+}
+
+// expectOK checks the "1|OK" reply of the logistics form APIs; failures come back as "0|message".
+func expectOK(body []byte, action string) error {
+
+	code, message, _ := strings.Cut(strings.TrimSpace(string(body)), "|")
+	if code != "1" {
+		return fmt.Errorf("%s失敗 失敗原因 : %s", action, message)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package logistics
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/envelope"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// replyDoer records the request sent to ECPay and answers with reply.
+type replyDoer struct {
+	reply string
+	calls int
+	url   string
+	body  string
+}
+
+func (d *replyDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls++
+	d.url = req.URL.String()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	d.body = string(body)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(d.reply))}, nil
+}
+
+// form returns the url-encoded form of the last request.
+func (d *replyDoer) form(t *testing.T) url.Values {
+	t.Helper()
+	values, err := url.ParseQuery(d.body)
+	if err != nil {
+		t.Fatalf("request is not a form: %v", err)
+	}
+	return values
+}
+
+func testClient(doer client.Doer) *client.ECPayClient {
+	c := client.StageLogisticsB2C.Client(client.Stage)
+	c.HTTPClient = doer
+	return c
+}
+
+// envelopeReply encrypts data into the envelope ECPay answers the v2 logistics APIs with.
+func envelopeReply(t *testing.T, c *client.ECPayClient, data string) string {
+	t.Helper()
+	encrypted, err := helpers.EncryptData(data, c.HashKey, c.HashIV)
+	if err != nil {
+		t.Fatalf("EncryptData() error = %v", err)
+	}
+	body, err := json.Marshal(envelope.Response{MerchantID: c.MerchantID, TransCode: 1, Data: encrypted})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return string(body)
+}
+
+func TestRedirectToLogisticsSelection(t *testing.T) {
+	doer := &replyDoer{}
+	e := &ECPayLogistics{
+		BaseModel: model.BaseModel{Client: testClient(doer)},
+		Goods:     model.Goods{GoodsAmount: 100, GoodsName: "書"},
+	}
+	doer.reply = envelopeReply(t, e.Client, `{"RtnCode":1,"RtnMsg":"<form>page</form>"}`)
+
+	msg, err := e.RedirectToLogisticsSelection()
+	if err != nil || msg != "<form>page</form>" {
+		t.Errorf("RedirectToLogisticsSelection() = %q, %v; want the decrypted RtnMsg", msg, err)
+	}
+
+	request := envelope.Request{}
+	if err = json.Unmarshal([]byte(doer.body), &request); err != nil || request.MerchantID != "2000132" || request.Data == "" {
+		t.Errorf("request envelope = %s, %v", doer.body, err)
+	}
+
+	// 無法解析的回應: 回傳原始內容與錯誤
+	doer.reply = "<html>maintenance</html>"
+	if msg, err = e.RedirectToLogisticsSelection(); err == nil || msg != doer.reply {
+		t.Errorf("RedirectToLogisticsSelection() = %q, %v; want the raw body and an error", msg, err)
+	}
+
+	doer.reply = envelopeReply(t, e.Client, `{"RtnCode":0,"RtnMsg":"參數錯誤"}`)
+	var resultErr *envelope.ResultError
+	if _, err = e.RedirectToLogisticsSelection(); !errors.As(err, &resultErr) {
+		t.Errorf("RedirectToLogisticsSelection() error = %v, want a ResultError", err)
+	}
+}
+
+func TestLogisticsSelectionHTML(t *testing.T) {
+	doer := &replyDoer{reply: "<html>selection</html>"}
+	e := &ECPayLogistics{BaseModel: model.BaseModel{Client: testClient(doer)}}
+
+	page, err := e.LogisticsSelectionHTML()
+	if err != nil || page != doer.reply {
+		t.Errorf("LogisticsSelectionHTML() = %q, %v; want the page", page, err)
+	}
+}
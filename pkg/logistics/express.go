@@ -0,0 +1,291 @@
+package logistics
+
+import (
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/envelope"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+)
+
+// TempTradeRequest is the Data payload of the 全方位物流 RedirectToLogisticsSelection
+// and UpdateTempTrade APIs
+type TempTradeRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// TempLogisticsID 暫存物流訂單編號, 建立時帶 0 或空白
+	TempLogisticsID string `json:"TempLogisticsID,omitempty"`
+
+	// Goods 商品資訊
+	model.Goods `json:",inline"`
+
+	// GoodsWeight 商品重量 (公斤)
+	GoodsWeight float64 `json:"GoodsWeight,omitempty"`
+
+	// IsCollection 是否代收貨款 (Y/N)
+	IsCollection string `json:"IsCollection,omitempty"`
+
+	// Sender 寄件人資訊
+	model.Sender `json:",inline"`
+
+	// Receiver 收件人資訊
+	model.Receiver `json:",inline"`
+
+	// ReturnStoreID 退貨門市代號
+	ReturnStoreID string `json:"ReturnStoreID,omitempty"`
+
+	// Remark 備註
+	Remark string `json:"Remark,omitempty"`
+
+	// ServerReplyURL 物流狀態通知網址
+	ServerReplyURL string `json:"ServerReplyURL,omitempty"`
+
+	// ClientReplyURL 物流選擇完成後導回的網址
+	ClientReplyURL string `json:"ClientReplyURL,omitempty"`
+}
+
+// TempTradeResponse is the decrypted Data of the UpdateTempTrade response
+type TempTradeResponse struct {
+	// RtnCode 回應代碼, 1 為成功
+	RtnCode int `json:"RtnCode"`
+
+	// RtnMsg 回應訊息
+	RtnMsg string `json:"RtnMsg"`
+
+	// TempLogisticsID 暫存物流訂單編號
+	TempLogisticsID string `json:"TempLogisticsID,omitempty"`
+}
+
+// RedirectToLogisticsSelection 取得綠界物流選擇頁, 回傳頁面 HTML
+func (r *TempTradeRequest) RedirectToLogisticsSelection() (string, error) {
+	return r.RedirectToLogisticsSelectionContext(context.Background())
+}
+
+// RedirectToLogisticsSelectionContext is like RedirectToLogisticsSelection but carries ctx to the outgoing request.
+func (r *TempTradeRequest) RedirectToLogisticsSelectionContext(ctx context.Context) (string, error) {
+
+	body, err := r.redirectToLogisticsSelection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// redirectToLogisticsSelection posts r in an encrypted envelope and returns the raw reply.
+func (r *TempTradeRequest) redirectToLogisticsSelection(ctx context.Context) ([]byte, error) {
+
+	payload, err := envelope.Encode(r.Client, r.MerchantID, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.SendJSONContext(ctx, r.Client, client.APILogisticsRedirectToSelection, payload)
+}
+
+// UpdateTempTrade 更新暫存物流訂單
+func (r *TempTradeRequest) UpdateTempTrade() (*TempTradeResponse, error) {
+	return r.UpdateTempTradeContext(context.Background())
+}
+
+// UpdateTempTradeContext is like UpdateTempTrade but carries ctx to the outgoing request.
+func (r *TempTradeRequest) UpdateTempTradeContext(ctx context.Context) (*TempTradeResponse, error) {
+
+	resp, err := envelope.Call[*TempTradeRequest, TempTradeResponse](ctx, r.Client, client.APILogisticsUpdateTempTrade, r.MerchantID, r)
+	if err != nil {
+		return resp, fmt.Errorf("更新暫存物流訂單失敗 失敗原因 : %w", err)
+	}
+
+	return resp, nil
+}
+
+// CreateByTempTradeRequest is the Data payload of the CreateByTempTrade API
+type CreateByTempTradeRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// TempLogisticsID 暫存物流訂單編號
+	TempLogisticsID string `json:"TempLogisticsID"`
+
+	// MerchantTradeNo 特店交易編號, 未帶時由綠界產生
+	MerchantTradeNo string `json:"MerchantTradeNo,omitempty"`
+}
+
+// LogisticsOrder is the decrypted Data of the CreateByTempTrade and
+// QueryLogisticsTradeInfo responses
+type LogisticsOrder struct {
+	// RtnCode 回應代碼, 1 為成功
+	RtnCode int `json:"RtnCode"`
+
+	// RtnMsg 回應訊息
+	RtnMsg string `json:"RtnMsg"`
+
+	// LogisticsID 綠界物流訂單編號
+	LogisticsID string `json:"LogisticsID,omitempty"`
+
+	// TempLogisticsID 暫存物流訂單編號
+	TempLogisticsID string `json:"TempLogisticsID,omitempty"`
+
+	// MerchantTradeNo 特店交易編號
+	MerchantTradeNo string `json:"MerchantTradeNo,omitempty"`
+
+	// LogisticsType 物流類型
+	LogisticsType string `json:"LogisticsType,omitempty"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType,omitempty"`
+
+	// LogisticsStatus 物流狀態
+	LogisticsStatus string `json:"LogisticsStatus,omitempty"`
+
+	// LogisticsStatusName 物流狀態說明
+	LogisticsStatusName string `json:"LogisticsStatusName,omitempty"`
+
+	// UpdateStatusDate 物流狀態更新時間
+	UpdateStatusDate string `json:"UpdateStatusDate,omitempty"`
+
+	// Goods 商品資訊
+	model.Goods `json:",inline"`
+
+	// GoodsWeight 商品重量 (公斤)
+	GoodsWeight float64 `json:"GoodsWeight,omitempty"`
+
+	// HandlingCharge 物流費用
+	HandlingCharge int `json:"HandlingCharge,omitempty"`
+
+	// CollectionAmount 代收金額
+	CollectionAmount int `json:"CollectionAmount,omitempty"`
+
+	// Receiver 收件人資訊
+	model.Receiver `json:",inline"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo,omitempty"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty"`
+
+	// BookingNote 托運單號
+	BookingNote string `json:"BookingNote,omitempty"`
+
+	// ShipmentNo 配送編號
+	ShipmentNo string `json:"ShipmentNo,omitempty"`
+}
+
+// CreateByTempTrade 以暫存物流訂單建立正式物流訂單
+func (r *CreateByTempTradeRequest) CreateByTempTrade() (*LogisticsOrder, error) {
+	return r.CreateByTempTradeContext(context.Background())
+}
+
+// CreateByTempTradeContext is like CreateByTempTrade but carries ctx to the outgoing request.
+func (r *CreateByTempTradeRequest) CreateByTempTradeContext(ctx context.Context) (*LogisticsOrder, error) {
+
+	resp, err := envelope.Call[*CreateByTempTradeRequest, LogisticsOrder](ctx, r.Client, client.APILogisticsCreateByTempTrade, r.MerchantID, r)
+	if err != nil {
+		return resp, fmt.Errorf("建立正式物流訂單失敗 失敗原因 : %w", err)
+	}
+
+	return resp, nil
+}
+
+// QueryRequest is the Data payload of the 全方位物流 QueryLogisticsTradeInfo API.
+// Either LogisticsID or MerchantTradeNo identifies the order.
+type QueryRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// LogisticsID 綠界物流訂單編號
+	LogisticsID string `json:"LogisticsID,omitempty"`
+
+	// MerchantTradeNo 特店交易編號
+	MerchantTradeNo string `json:"MerchantTradeNo,omitempty"`
+}
+
+// Query 查詢物流訂單
+func (r *QueryRequest) Query() (*LogisticsOrder, error) {
+	return r.QueryContext(context.Background())
+}
+
+// QueryContext is like Query but carries ctx to the outgoing request.
+func (r *QueryRequest) QueryContext(ctx context.Context) (*LogisticsOrder, error) {
+
+	resp, err := envelope.Call[*QueryRequest, LogisticsOrder](ctx, r.Client, client.APILogisticsQueryTradeInfoV2, r.MerchantID, r)
+	if err != nil {
+		return resp, fmt.Errorf("查詢物流訂單失敗 失敗原因 : %w", err)
+	}
+
+	return resp, nil
+}
+
+// PrintRequest is the Data payload of the 全方位物流 PrintTradeDocument API
+type PrintRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// LogisticsID 綠界物流訂單編號, 同一物流子類型可一次列印多筆
+	LogisticsID []string `json:"LogisticsID"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType"`
+}
+
+// Print 列印託運單, 回傳託運單頁面 HTML
+func (r *PrintRequest) Print() (string, error) {
+	return r.PrintContext(context.Background())
+}
+
+// PrintContext is like Print but carries ctx to the outgoing request.
+func (r *PrintRequest) PrintContext(ctx context.Context) (string, error) {
+
+	payload, err := envelope.Encode(r.Client, r.MerchantID, r)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := helpers.SendJSONContext(ctx, r.Client, client.APILogisticsPrintTradeDocumentV2, payload)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// TestDataRequest is the Data payload of the CreateTestData API
+type TestDataRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType,omitempty"`
+
+	// ClientReplyURL 完成後導回的網址
+	ClientReplyURL string `json:"ClientReplyURL,omitempty"`
+}
+
+// CreateTestData 產生 B2C 測試標籤資料
+func (r *TestDataRequest) CreateTestData() (*LogisticsOrder, error) {
+	return r.CreateTestDataContext(context.Background())
+}
+
+// CreateTestDataContext is like CreateTestData but carries ctx to the outgoing request.
+func (r *TestDataRequest) CreateTestDataContext(ctx context.Context) (*LogisticsOrder, error) {
+
+	resp, err := envelope.Call[*TestDataRequest, LogisticsOrder](ctx, r.Client, client.APILogisticsCreateTestData, r.MerchantID, r)
+	if err != nil {
+		return resp, fmt.Errorf("產生測試標籤資料失敗 失敗原因 : %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,231 @@
+package logistics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+)
+
+const (
+	// TemperatureNormal 常溫
+	TemperatureNormal = "0001"
+
+	// TemperatureRefrigerated 冷藏
+	TemperatureRefrigerated = "0002"
+
+	// TemperatureFrozen 冷凍
+	TemperatureFrozen = "0003"
+)
+
+const (
+	// Specification60 60cm
+	Specification60 = "0001"
+
+	// Specification90 90cm
+	Specification90 = "0002"
+
+	// Specification120 120cm
+	Specification120 = "0003"
+
+	// Specification150 150cm
+	Specification150 = "0004"
+)
+
+const (
+	// DistanceSameCity 同縣市
+	DistanceSameCity = "00"
+
+	// DistanceOtherCity 外縣市
+	DistanceOtherCity = "01"
+
+	// DistanceIsland 離島
+	DistanceIsland = "02"
+)
+
+const (
+	// PickupMorning 預定取件時段 9~12 時
+	PickupMorning = "1"
+
+	// PickupAfternoon 預定取件時段 12~17 時
+	PickupAfternoon = "2"
+
+	// PickupEvening 預定取件時段 17~20 時
+	PickupEvening = "3"
+
+	// PickupAnyTime 預定取件時段 不限時
+	PickupAnyTime = "4"
+)
+
+const (
+	// DeliveryBefore13 預定送達時段 13 時前
+	DeliveryBefore13 = "1"
+
+	// DeliveryAfternoon 預定送達時段 14~18 時
+	DeliveryAfternoon = "2"
+
+	// DeliveryAnyTime 預定送達時段 不限時
+	DeliveryAnyTime = "4"
+)
+
+// PostMaxWeight 郵局宅配單件重量上限 (公斤)
+const PostMaxWeight = 20
+
+// ErrInvalidHomeOrder 宅配訂單參數組合不正確
+var ErrInvalidHomeOrder = errors.New("invalid home delivery order")
+
+// HomeOrderRequest creates a home delivery (TCAT / POST) order through Express/Create
+type HomeOrderRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// Merchant 特店資訊, MerchantTradeDate 未帶時使用目前時間
+	model.Merchant `json:",inline"`
+
+	// LogisticsType 物流類型, 固定為 HOME
+	LogisticsType string `json:"LogisticsType" form:"LogisticsType"`
+
+	// LogisticsSubType 物流子類型 (TCAT, POST)
+	LogisticsSubType string `json:"LogisticsSubType" form:"LogisticsSubType"`
+
+	// Goods 商品資訊
+	model.Goods `json:",inline"`
+
+	// GoodsWeight 商品重量 (公斤), 郵局必填
+	GoodsWeight float64 `json:"GoodsWeight,omitempty" form:"GoodsWeight"`
+
+	// Sender 寄件人資訊, SenderZipCode 與 SenderAddress 必填
+	model.Sender `json:",inline"`
+
+	// Receiver 收件人資訊, ReceiverZipCode 與 ReceiverAddress 必填
+	model.Receiver `json:",inline"`
+
+	// Temperature 溫層 (TemperatureNormal, TemperatureRefrigerated, TemperatureFrozen)
+	Temperature string `json:"Temperature,omitempty" form:"Temperature"`
+
+	// Distance 距離 (DistanceSameCity, DistanceOtherCity, DistanceIsland)
+	Distance string `json:"Distance,omitempty" form:"Distance"`
+
+	// Specification 規格 (Specification60 ~ Specification150)
+	Specification string `json:"Specification,omitempty" form:"Specification"`
+
+	// ScheduledPickupTime 預定取件時段
+	ScheduledPickupTime string `json:"ScheduledPickupTime,omitempty" form:"ScheduledPickupTime"`
+
+	// ScheduledDeliveryTime 預定送達時段
+	ScheduledDeliveryTime string `json:"ScheduledDeliveryTime,omitempty" form:"ScheduledDeliveryTime"`
+
+	// TradeDesc 交易描述
+	TradeDesc string `json:"TradeDesc,omitempty" form:"TradeDesc"`
+
+	// ServerReplyURL 物流狀態通知網址
+	ServerReplyURL string `json:"ServerReplyURL" form:"ServerReplyURL"`
+
+	// Remark 備註
+	Remark string `json:"Remark,omitempty" form:"Remark"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+func invalidHomeOrder(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidHomeOrder, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the fields and the carrier / temperature / size combination before
+// the order is sent; every error wraps ErrInvalidHomeOrder.
+func (r *HomeOrderRequest) Validate() error {
+
+	if r.LogisticsSubType != SubTypeTCAT && r.LogisticsSubType != SubTypePOST {
+		return invalidHomeOrder("宅配物流子類型須為 TCAT 或 POST, 收到 %q", r.LogisticsSubType)
+	}
+	if r.MerchantTradeNo == "" {
+		return invalidHomeOrder("缺少 MerchantTradeNo")
+	}
+	if r.GoodsAmount <= 0 {
+		return invalidHomeOrder("GoodsAmount 須大於 0")
+	}
+	if r.SenderZipCode == "" || r.SenderAddress == "" {
+		return invalidHomeOrder("缺少寄件人郵遞區號或地址")
+	}
+	if r.ReceiverZipCode == "" || r.ReceiverAddress == "" {
+		return invalidHomeOrder("缺少收件人郵遞區號或地址")
+	}
+	if r.ServerReplyURL == "" {
+		return invalidHomeOrder("缺少 ServerReplyURL")
+	}
+
+	if err := validateHomeOptions(r.Temperature, r.Specification, r.Distance, r.ScheduledPickupTime, r.ScheduledDeliveryTime); err != nil {
+		return err
+	}
+
+	chilled := r.Temperature == TemperatureRefrigerated || r.Temperature == TemperatureFrozen
+	if r.LogisticsSubType == SubTypePOST {
+		if chilled {
+			return invalidHomeOrder("郵局宅配僅支援常溫")
+		}
+		if r.GoodsWeight <= 0 || r.GoodsWeight > PostMaxWeight {
+			return invalidHomeOrder("郵局宅配 GoodsWeight 須介於 0 ~ %d 公斤", PostMaxWeight)
+		}
+		return nil
+	}
+
+	if chilled && r.Specification == Specification150 {
+		return invalidHomeOrder("黑貓冷藏 / 冷凍最大規格為 120cm")
+	}
+	if r.Distance == DistanceIsland && chilled {
+		return invalidHomeOrder("黑貓冷藏 / 冷凍不配送離島")
+	}
+
+	return nil
+}
+
+// validateHomeOptions rejects option codes ECPay does not define; empty values use ECPay's defaults.
+func validateHomeOptions(temperature, specification, distance, pickup, delivery string) error {
+	switch temperature {
+	case "", TemperatureNormal, TemperatureRefrigerated, TemperatureFrozen:
+	default:
+		return invalidHomeOrder("未知的溫層 %q", temperature)
+	}
+	switch specification {
+	case "", Specification60, Specification90, Specification120, Specification150:
+	default:
+		return invalidHomeOrder("未知的規格 %q", specification)
+	}
+	switch distance {
+	case "", DistanceSameCity, DistanceOtherCity, DistanceIsland:
+	default:
+		return invalidHomeOrder("未知的距離 %q", distance)
+	}
+	switch pickup {
+	case "", PickupMorning, PickupAfternoon, PickupEvening, PickupAnyTime:
+	default:
+		return invalidHomeOrder("未知的預定取件時段 %q", pickup)
+	}
+	switch delivery {
+	case "", DeliveryBefore13, DeliveryAfternoon, DeliveryAnyTime:
+	default:
+		return invalidHomeOrder("未知的預定送達時段 %q", delivery)
+	}
+	return nil
+}
+
+// Create 建立宅配物流訂單. The result carries the AllPayLogisticsID and the BookingNote (托運單號).
+func (r *HomeOrderRequest) Create() (*CreateResult, error) {
+	return r.CreateContext(context.Background())
+}
+
+// CreateContext is like Create but carries ctx to the outgoing request.
+func (r *HomeOrderRequest) CreateContext(ctx context.Context) (*CreateResult, error) {
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 在複本上補齊固定欄位, 不修改呼叫端的請求
+	request := *r
+	request.LogisticsType = LogisticsTypeHome
+	request.MerchantTradeDate = tradeDate(r.MerchantTradeDate)
+
+	return createOrder(ctx, r.Client, &request)
+}
@@ -0,0 +1,112 @@
+package logistics
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"net/url"
+	"testing"
+)
+
+func validHomeOrder() HomeOrderRequest {
+	return HomeOrderRequest{
+		Merchant:         model.Merchant{MerchantID: "2000132", MerchantTradeNo: "H0001"},
+		LogisticsSubType: SubTypeTCAT,
+		Goods:            model.Goods{GoodsName: "書", GoodsAmount: 100},
+		Sender:           model.Sender{SenderName: "倉庫", SenderPhone: "0212345678", SenderZipCode: "114", SenderAddress: "台北市內湖區"},
+		Receiver:         model.Receiver{ReceiverName: "王小明", ReceiverCellPhone: "0912345678", ReceiverZipCode: "100", ReceiverAddress: "台北市中正區"},
+		ServerReplyURL:   "https://example.com/reply",
+	}
+}
+
+func TestHomeOrderRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(r *HomeOrderRequest)
+		wantErr bool
+	}{
+		{name: "tcat", modify: func(r *HomeOrderRequest) {}},
+		{
+			name: "tcat frozen 120cm",
+			modify: func(r *HomeOrderRequest) {
+				r.Temperature, r.Specification, r.Distance = TemperatureFrozen, Specification120, DistanceOtherCity
+			},
+		},
+		{name: "tcat normal 150cm island", modify: func(r *HomeOrderRequest) { r.Specification, r.Distance = Specification150, DistanceIsland }},
+		{name: "post", modify: func(r *HomeOrderRequest) { r.LogisticsSubType, r.GoodsWeight = SubTypePOST, 5 }},
+		{name: "post at weight limit", modify: func(r *HomeOrderRequest) { r.LogisticsSubType, r.GoodsWeight = SubTypePOST, PostMaxWeight }},
+		{name: "cvs subtype", modify: func(r *HomeOrderRequest) { r.LogisticsSubType = SubTypeFAMI }, wantErr: true},
+		{name: "no subtype", modify: func(r *HomeOrderRequest) { r.LogisticsSubType = "" }, wantErr: true},
+		{name: "no merchant trade no", modify: func(r *HomeOrderRequest) { r.MerchantTradeNo = "" }, wantErr: true},
+		{name: "no goods amount", modify: func(r *HomeOrderRequest) { r.GoodsAmount = 0 }, wantErr: true},
+		{name: "no sender zip code", modify: func(r *HomeOrderRequest) { r.SenderZipCode = "" }, wantErr: true},
+		{name: "no receiver address", modify: func(r *HomeOrderRequest) { r.ReceiverAddress = "" }, wantErr: true},
+		{name: "no reply url", modify: func(r *HomeOrderRequest) { r.ServerReplyURL = "" }, wantErr: true},
+		{name: "unknown temperature", modify: func(r *HomeOrderRequest) { r.Temperature = "0004" }, wantErr: true},
+		{name: "unknown specification", modify: func(r *HomeOrderRequest) { r.Specification = "0005" }, wantErr: true},
+		{name: "unknown distance", modify: func(r *HomeOrderRequest) { r.Distance = "03" }, wantErr: true},
+		{name: "unknown pickup time", modify: func(r *HomeOrderRequest) { r.ScheduledPickupTime = "5" }, wantErr: true},
+		{name: "unknown delivery time", modify: func(r *HomeOrderRequest) { r.ScheduledDeliveryTime = "3" }, wantErr: true},
+		{
+			name: "post chilled",
+			modify: func(r *HomeOrderRequest) {
+				r.LogisticsSubType, r.GoodsWeight, r.Temperature = SubTypePOST, 5, TemperatureRefrigerated
+			},
+			wantErr: true,
+		},
+		{name: "post without weight", modify: func(r *HomeOrderRequest) { r.LogisticsSubType = SubTypePOST }, wantErr: true},
+		{name: "post overweight", modify: func(r *HomeOrderRequest) { r.LogisticsSubType, r.GoodsWeight = SubTypePOST, PostMaxWeight+0.5 }, wantErr: true},
+		{name: "tcat chilled 150cm", modify: func(r *HomeOrderRequest) { r.Temperature, r.Specification = TemperatureRefrigerated, Specification150 }, wantErr: true},
+		{name: "tcat frozen island", modify: func(r *HomeOrderRequest) { r.Temperature, r.Distance = TemperatureFrozen, DistanceIsland }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validHomeOrder()
+			tt.modify(&r)
+
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidHomeOrder) {
+				t.Errorf("Validate() error = %v, want it to wrap ErrInvalidHomeOrder", err)
+			}
+		})
+	}
+}
+
+func TestHomeOrderRequestCreate(t *testing.T) {
+	doer := &replyDoer{}
+	r := validHomeOrder()
+	r.Client = testClient(doer)
+	doer.reply = createReply(r.Client, url.Values{
+		"MerchantID":        {"2000132"},
+		"MerchantTradeNo":   {"H0001"},
+		"RtnCode":           {"300"},
+		"AllPayLogisticsID": {"1720"},
+		"BookingNote":       {"9012345678"},
+	})
+
+	result, err := r.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if result.AllPayLogisticsID != "1720" || result.BookingNote != "9012345678" {
+		t.Errorf("Create() = %+v", result)
+	}
+	if r.LogisticsType != "" || r.MerchantTradeDate != "" {
+		t.Errorf("Create() changed the request: LogisticsType = %q, MerchantTradeDate = %q", r.LogisticsType, r.MerchantTradeDate)
+	}
+
+	form := doer.form(t)
+	if form.Get("LogisticsType") != LogisticsTypeHome || form.Get("MerchantTradeDate") == "" || form.Get("SenderZipCode") != "114" {
+		t.Errorf("sent %s", doer.body)
+	}
+
+	doer.calls = 0
+	r.Temperature = TemperatureFrozen
+	r.Distance = DistanceIsland
+	if _, err = r.Create(); !errors.Is(err, ErrInvalidHomeOrder) || doer.calls != 0 {
+		t.Errorf("Create() error = %v, calls = %d; want an unsent invalid order", err, doer.calls)
+	}
+}
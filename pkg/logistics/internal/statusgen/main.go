@@ -0,0 +1,94 @@
+// Command statusgen generates status_codes_gen.go from status_codes.tsv.
+// Run it through go generate in pkg/logistics.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// knownCarriers 可出現在 carrier 欄位的業者, 須與 status.go 的 Carrier 常數一致
+var knownCarriers = map[string]bool{
+	"*":       true,
+	"UNIMART": true,
+	"FAMI":    true,
+	"HILIFE":  true,
+	"OKMART":  true,
+	"TCAT":    true,
+	"POST":    true,
+}
+
+func main() {
+
+	in := flag.String("in", "status_codes.tsv", "status code table")
+	out := flag.String("out", "status_codes_gen.go", "generated Go file")
+	flag.Parse()
+
+	file, err := os.Open(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by statusgen from status_codes.tsv; DO NOT EDIT.\n\n")
+	buf.WriteString("package logistics\n\n")
+	buf.WriteString("var statusCatalogue = map[statusKey]Status{\n")
+
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 5 {
+			log.Fatalf("%s:%d: want 5 tab-separated fields, got %d", *in, line, len(fields))
+		}
+		carriers, code, state, zh, en := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+		if _, err := strconv.Atoi(code); err != nil {
+			log.Fatalf("%s:%d: invalid code %q", *in, line, code)
+		}
+
+		// 多家業者共用同一代碼時以逗號分隔, 例如 FAMI,HILIFE,OKMART
+		for _, carrier := range strings.Split(carriers, ",") {
+			if !knownCarriers[carrier] {
+				log.Fatalf("%s:%d: unknown carrier %q", *in, line, carrier)
+			}
+			key := carrier + "/" + code
+			if seen[key] {
+				log.Fatalf("%s:%d: duplicate code %s for carrier %s", *in, line, code, carrier)
+			}
+			seen[key] = true
+
+			carrierExpr := "carrierAny"
+			if carrier != "*" {
+				carrierExpr = "Carrier" + carrier
+			}
+			fmt.Fprintf(&buf, "\t{%s, %s}: {Carrier: %s, Code: %s, State: State%s, Chinese: %q, English: %q},\n",
+				carrierExpr, code, carrierExpr, code, state, zh, en)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	buf.WriteString("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,294 @@
+package logistics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// StoreTypeReceiver 更新取件門市
+	StoreTypeReceiver = "01"
+
+	// StoreTypeReturn 更新退件門市
+	StoreTypeReturn = "02"
+)
+
+// ShipmentDateFormat 出貨日期格式
+const ShipmentDateFormat = "2006/01/02"
+
+// TradeInfoRequest queries one logistics order by its AllPayLogisticsID
+type TradeInfoRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID" form:"AllPayLogisticsID"`
+
+	// TimeStamp 驗證時間, 未帶時使用目前時間
+	TimeStamp int64 `json:"TimeStamp" form:"TimeStamp"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// TradeInfo is the logistics order returned by QueryLogisticsTradeInfo
+type TradeInfo struct {
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// MerchantTradeNo 特店交易編號
+	MerchantTradeNo string `json:"MerchantTradeNo" form:"MerchantTradeNo"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID" form:"AllPayLogisticsID"`
+
+	// LogisticsType 物流類型, 例如 CVS_FAMI, HOME_TCAT
+	LogisticsType string `json:"LogisticsType" form:"LogisticsType"`
+
+	// LogisticsStatus 物流狀態
+	LogisticsStatus string `json:"LogisticsStatus" form:"LogisticsStatus"`
+
+	// TradeDate 訂單成立時間
+	TradeDate string `json:"TradeDate,omitempty" form:"TradeDate"`
+
+	// GoodsName 商品名稱
+	GoodsName string `json:"GoodsName,omitempty" form:"GoodsName"`
+
+	// GoodsAmount 商品金額
+	GoodsAmount int `json:"GoodsAmount" form:"GoodsAmount"`
+
+	// GoodsWeight 商品重量 (公斤)
+	GoodsWeight float64 `json:"GoodsWeight,omitempty" form:"GoodsWeight"`
+
+	// ActualWeight 實際重量 (公斤)
+	ActualWeight float64 `json:"ActualWeight,omitempty" form:"ActualWeight"`
+
+	// HandlingCharge 物流費用
+	HandlingCharge int `json:"HandlingCharge" form:"HandlingCharge"`
+
+	// CollectionAmount 代收金額
+	CollectionAmount int `json:"CollectionAmount,omitempty" form:"CollectionAmount"`
+
+	// CollectionChargeFee 代收手續費
+	CollectionChargeFee int `json:"CollectionChargeFee,omitempty" form:"CollectionChargeFee"`
+
+	// CollectionAllocateDate 代收貨款撥款日期
+	CollectionAllocateDate string `json:"CollectionAllocateDate,omitempty" form:"CollectionAllocateDate"`
+
+	// CollectionAllocateAmount 代收貨款撥款金額
+	CollectionAllocateAmount int `json:"CollectionAllocateAmount,omitempty" form:"CollectionAllocateAmount"`
+
+	// ShipChargeDate 物流費用扣款日期
+	ShipChargeDate string `json:"ShipChargeDate,omitempty" form:"ShipChargeDate"`
+
+	// ShipmentNo 配送編號
+	ShipmentNo string `json:"ShipmentNo,omitempty" form:"ShipmentNo"`
+
+	// BookingNote 托運單號
+	BookingNote string `json:"BookingNote,omitempty" form:"BookingNote"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo,omitempty" form:"CVSPaymentNo"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty" form:"CVSValidationNo"`
+}
+
+// SubType returns the LogisticsSubType part of LogisticsType, e.g. FAMI for CVS_FAMI.
+func (t *TradeInfo) SubType() string {
+	if _, subType, found := strings.Cut(t.LogisticsType, "_"); found {
+		return subType
+	}
+	return t.LogisticsType
+}
+
+// Status returns the catalogue entry of the order's LogisticsStatus.
+func (t *TradeInfo) Status() (Status, bool) {
+	code, err := strconv.Atoi(t.LogisticsStatus)
+	if err != nil {
+		return Status{Carrier: CarrierOf(t.SubType()), State: StateUnknown}, false
+	}
+	return LookupStatus(t.SubType(), code)
+}
+
+// Query 查詢物流訂單
+func (r *TradeInfoRequest) Query() (*TradeInfo, error) {
+	return r.QueryContext(context.Background())
+}
+
+// QueryContext is like Query but carries ctx to the outgoing request.
+func (r *TradeInfoRequest) QueryContext(ctx context.Context) (*TradeInfo, error) {
+
+	// 每次查詢在複本上計算 TimeStamp, 不修改呼叫端的請求
+	request := *r
+	if request.TimeStamp == 0 {
+		request.TimeStamp = time.Now().Unix()
+	}
+
+	body, err := sendSigned(ctx, r.Client, client.APILogisticsQueryTradeInfo, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查詢結果為 key=value&... 格式, 失敗時為 "0|錯誤訊息"
+	body = bytes.TrimSpace(body)
+	if bytes.HasPrefix(body, []byte("0|")) {
+		return nil, expectOK(body, "查詢物流訂單")
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("查詢物流訂單失敗 失敗原因 : %s", body)
+	}
+
+	if err = validation.ValidateCheckMacValue(values, r.Client.HashKey, r.Client.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+		return nil, err
+	}
+
+	info := &TradeInfo{}
+	if err = helpers.BindFormValues(values, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// UpdateShipmentRequest changes the shipment date or the pickup store of a B2C order
+type UpdateShipmentRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID" form:"AllPayLogisticsID"`
+
+	// ShipmentDate 物流訂單出貨日期 (ShipmentDateFormat)
+	ShipmentDate string `json:"ShipmentDate,omitempty" form:"ShipmentDate"`
+
+	// ReceiverStoreID 新的取件門市代號
+	ReceiverStoreID string `json:"ReceiverStoreID,omitempty" form:"ReceiverStoreID"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// Update 更新出貨資訊
+func (r *UpdateShipmentRequest) Update() error {
+	return r.UpdateContext(context.Background())
+}
+
+// UpdateContext is like Update but carries ctx to the outgoing request.
+func (r *UpdateShipmentRequest) UpdateContext(ctx context.Context) error {
+
+	if r.ShipmentDate == "" && r.ReceiverStoreID == "" {
+		return fmt.Errorf("更新出貨資訊須帶入 ShipmentDate 或 ReceiverStoreID")
+	}
+
+	body, err := sendSigned(ctx, r.Client, client.APILogisticsUpdateShipmentInfo, r)
+	if err != nil {
+		return err
+	}
+
+	return expectOK(body, "更新出貨資訊")
+}
+
+// UpdateStoreRequest changes the pickup or return store of a C2C order
+type UpdateStoreRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID" form:"AllPayLogisticsID"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo" form:"CVSPaymentNo"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty" form:"CVSValidationNo"`
+
+	// StoreType 門市類型 (StoreTypeReceiver, StoreTypeReturn)
+	StoreType string `json:"StoreType" form:"StoreType"`
+
+	// ReceiverStoreID 新的取件門市代號, StoreType 為 01 時必填
+	ReceiverStoreID string `json:"ReceiverStoreID,omitempty" form:"ReceiverStoreID"`
+
+	// ReturnStoreID 新的退件門市代號, StoreType 為 02 時必填
+	ReturnStoreID string `json:"ReturnStoreID,omitempty" form:"ReturnStoreID"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// Update 更新店到店門市
+func (r *UpdateStoreRequest) Update() error {
+	return r.UpdateContext(context.Background())
+}
+
+// UpdateContext is like Update but carries ctx to the outgoing request.
+func (r *UpdateStoreRequest) UpdateContext(ctx context.Context) error {
+
+	switch {
+	case r.StoreType == StoreTypeReceiver && r.ReceiverStoreID == "":
+		return fmt.Errorf("更新取件門市缺少 ReceiverStoreID")
+	case r.StoreType == StoreTypeReturn && r.ReturnStoreID == "":
+		return fmt.Errorf("更新退件門市缺少 ReturnStoreID")
+	case r.StoreType != StoreTypeReceiver && r.StoreType != StoreTypeReturn:
+		return fmt.Errorf("未知的門市類型 %q", r.StoreType)
+	}
+
+	body, err := sendSigned(ctx, r.Client, client.APILogisticsUpdateStoreInfo, r)
+	if err != nil {
+		return err
+	}
+
+	return expectOK(body, "更新店到店門市")
+}
+
+// CancelC2CRequest cancels a C2C order that has not been dropped off yet
+type CancelC2CRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID" form:"AllPayLogisticsID"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo" form:"CVSPaymentNo"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty" form:"CVSValidationNo"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// Cancel 取消店到店訂單
+func (r *CancelC2CRequest) Cancel() error {
+	return r.CancelContext(context.Background())
+}
+
+// CancelContext is like Cancel but carries ctx to the outgoing request.
+func (r *CancelC2CRequest) CancelContext(ctx context.Context) error {
+
+	body, err := sendSigned(ctx, r.Client, client.APILogisticsCancelC2COrder, r)
+	if err != nil {
+		return err
+	}
+
+	return expectOK(body, "取消店到店訂單")
+}
@@ -0,0 +1,65 @@
+package logistics
+
+import (
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTradeInfoRequestQuery(t *testing.T) {
+	doer := &replyDoer{}
+	r := &TradeInfoRequest{Client: testClient(doer), MerchantID: "2000132", AllPayLogisticsID: "1718"}
+	reply := url.Values{
+		"MerchantID":        {"2000132"},
+		"MerchantTradeNo":   {"L0001"},
+		"AllPayLogisticsID": {"1718"},
+		"LogisticsType":     {"HOME_TCAT"},
+		"LogisticsStatus":   {"3003"},
+		"GoodsAmount":       {"100"},
+		"HandlingCharge":    {"130"},
+		"BookingNote":       {"9012345678"},
+	}
+	reply.Set("CheckMacValue", helpers.GenerateCheckMacValue(reply, r.Client.HashKey, r.Client.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)))
+	doer.reply = reply.Encode()
+
+	info, err := r.Query()
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if info.MerchantTradeNo != "L0001" || info.SubType() != SubTypeTCAT || info.HandlingCharge != 130 || info.BookingNote != "9012345678" {
+		t.Errorf("Query() = %+v", info)
+	}
+	if r.TimeStamp != 0 {
+		t.Errorf("Query() set the request's TimeStamp to %d", r.TimeStamp)
+	}
+
+	form := doer.form(t)
+	if !strings.HasSuffix(doer.url, client.APILogisticsQueryTradeInfo.Path) {
+		t.Errorf("sent to %s", doer.url)
+	}
+	if form.Get("TimeStamp") == "" || form.Get("AllPayLogisticsID") != "1718" {
+		t.Errorf("sent %s", doer.body)
+	}
+	if err = validation.ValidateCheckMacValue(form, r.Client.HashKey, r.Client.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+		t.Errorf("form is not MD5 signed: %v", err)
+	}
+
+	tampered := url.Values{}
+	for key, value := range reply {
+		tampered[key] = value
+	}
+	tampered.Set("HandlingCharge", "0")
+	for name, body := range map[string]string{
+		"failure":  "0|查無資料",
+		"tampered": tampered.Encode(),
+		"json":     `{"MerchantID":"2000132","AllPayLogisticsID":"1718"}`,
+	} {
+		doer.reply = body
+		if _, err = r.Query(); err == nil {
+			t.Errorf("%s: Query() accepted %s", name, body)
+		}
+	}
+}
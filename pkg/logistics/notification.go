@@ -0,0 +1,141 @@
+package logistics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/envelope"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/notification"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// maxNotificationBody 物流狀態通知內容上限
+const maxNotificationBody = 1 << 20
+
+// StatusUpdate is the shipment status change ECPay posts to ServerReplyURL
+type StatusUpdate struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// RtnCode 物流狀態代碼, 例如 2030 (商品已送至物流中心), 3024 (貨件已至物流中心)
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 物流狀態說明
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID,omitempty" form:"AllPayLogisticsID"`
+
+	// LogisticsID 全方位物流 綠界物流訂單編號
+	LogisticsID string `json:"LogisticsID,omitempty" form:"LogisticsID"`
+
+	// LogisticsType 物流類型
+	LogisticsType string `json:"LogisticsType,omitempty" form:"LogisticsType"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType,omitempty" form:"LogisticsSubType"`
+
+	// GoodsAmount 商品金額
+	GoodsAmount int `json:"GoodsAmount,omitempty" form:"GoodsAmount"`
+
+	// UpdateStatusDate 物流狀態更新時間
+	UpdateStatusDate string `json:"UpdateStatusDate,omitempty" form:"UpdateStatusDate"`
+
+	// Receiver 收件人資訊
+	model.Receiver `json:",inline"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo,omitempty" form:"CVSPaymentNo"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty" form:"CVSValidationNo"`
+
+	// BookingNote 托運單號
+	BookingNote string `json:"BookingNote,omitempty" form:"BookingNote"`
+
+	// CheckMacValue 檢查碼
+	CheckMacValue string `json:"CheckMacValue,omitempty" form:"CheckMacValue"`
+}
+
+// ID returns the ECPay logistics order number, whichever API version sent the update.
+func (s *StatusUpdate) ID() string {
+	if s.LogisticsID != "" {
+		return s.LogisticsID
+	}
+	return s.AllPayLogisticsID
+}
+
+// StatusHandler receives the shipment status changes ECPay posts to ServerReplyURL.
+// Both the form notification signed with an MD5 CheckMacValue and the 全方位物流 (v2)
+// AES-encrypted JSON envelope are accepted. The verified update is passed to OnStatus
+// and ECPay is answered with "1|OK", or "0|<error>" when verification or the callback fails.
+type StatusHandler struct {
+	// Client 提供驗證 CheckMacValue 與解密的 HashKey / HashIV
+	Client *client.ECPayClient
+
+	// OnStatus 收到已驗證的物流狀態時呼叫, 回傳錯誤時綠界會重新通知
+	OnStatus func(ctx context.Context, update *StatusUpdate) error
+}
+
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		update := &StatusUpdate{}
+		notification.Serve(w, r, h.Client, update, func() error {
+			return h.dispatch(r.Context(), update)
+		}, helpers.WithHashAlgorithm(helpers.HashMD5))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	update, err := h.decodeJSON(r)
+	if err != nil {
+		h.Client.Log().Warn("Rejected ECPay logistics notification", "path", r.URL.Path, "error", err)
+		notification.Reply(w, err)
+		return
+	}
+
+	if err = h.dispatch(r.Context(), update); err != nil {
+		h.Client.Log().Error("ECPay logistics notification callback failed", "path", r.URL.Path, "error", err)
+		notification.Reply(w, err)
+		return
+	}
+
+	notification.Reply(w, nil)
+}
+
+// decodeJSON decrypts the v2 envelope. The status code travels in RtnCode, so the
+// *envelope.ResultError reported for every code other than 1 is not a failure here.
+func (h *StatusHandler) decodeJSON(r *http.Request) (*StatusUpdate, error) {
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxNotificationBody))
+	if err != nil {
+		return nil, fmt.Errorf("error reading notification body: %w", err)
+	}
+
+	update, err := envelope.Decode[StatusUpdate](h.Client, body)
+	var resultErr *envelope.ResultError
+	if errors.As(err, &resultErr) {
+		return update, nil
+	}
+
+	return update, err
+}
+
+func (h *StatusHandler) dispatch(ctx context.Context, update *StatusUpdate) error {
+	if h.OnStatus == nil {
+		return nil
+	}
+	return h.OnStatus(ctx, update)
+}
@@ -0,0 +1,124 @@
+package logistics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func statusForm(c *client.ECPayClient, algorithm helpers.HashAlgorithm) url.Values {
+	values := url.Values{
+		"MerchantID":        {"2000132"},
+		"MerchantTradeNo":   {"L20240101"},
+		"RtnCode":           {"2030"},
+		"RtnMsg":            {"商品已送至物流中心"},
+		"AllPayLogisticsID": {"1718546"},
+		"GoodsAmount":       {"100"},
+		"UpdateStatusDate":  {"2024/01/01 12:00:00"},
+	}
+	values.Set("CheckMacValue", helpers.GenerateCheckMacValue(values, c.HashKey, c.HashIV, helpers.WithHashAlgorithm(algorithm)))
+	return values
+}
+
+func TestStatusHandlerForm(t *testing.T) {
+	c := client.StageLogisticsB2C.Client(client.Stage)
+
+	tampered := statusForm(c, helpers.HashMD5)
+	tampered.Set("RtnCode", "3022")
+
+	tests := []struct {
+		name       string
+		method     string
+		form       url.Values
+		callback   error
+		wantStatus int
+		wantBody   string
+		wantCalled bool
+	}{
+		{name: "verified", method: http.MethodPost, form: statusForm(c, helpers.HashMD5), wantStatus: http.StatusOK, wantBody: "1|OK", wantCalled: true},
+		{name: "tampered", method: http.MethodPost, form: tampered, wantStatus: http.StatusOK, wantBody: "0|CheckMacValue mismatch"},
+		{name: "sha256 signed", method: http.MethodPost, form: statusForm(c, helpers.HashSHA256), wantStatus: http.StatusOK, wantBody: "0|CheckMacValue mismatch"},
+		{name: "callback error", method: http.MethodPost, form: statusForm(c, helpers.HashMD5), callback: errors.New("busy"), wantStatus: http.StatusOK, wantBody: "0|busy", wantCalled: true},
+		{name: "get", method: http.MethodGet, form: url.Values{}, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *StatusUpdate
+			handler := &StatusHandler{Client: c, OnStatus: func(_ context.Context, update *StatusUpdate) error {
+				got = update
+				return tt.callback
+			}}
+
+			req := httptest.NewRequest(tt.method, "/ecpay/logistics", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("OnStatus called = %v, want %v", got != nil, tt.wantCalled)
+			}
+			if got != nil && (got.RtnCode != 2030 || got.ID() != "1718546" || got.MerchantTradeNo != "L20240101") {
+				t.Errorf("unexpected update %+v", got)
+			}
+		})
+	}
+}
+
+func TestStatusHandlerJSON(t *testing.T) {
+	c := client.StageLogisticsB2C.Client(client.Stage)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantBody   string
+		wantCalled bool
+	}{
+		{
+			name:       "status code other than 1",
+			body:       envelopeReply(t, c, `{"RtnCode":3024,"RtnMsg":"貨件已至物流中心","LogisticsID":"2401010001"}`),
+			wantBody:   "1|OK",
+			wantCalled: true,
+		},
+		{name: "not an envelope", body: "<html>", wantBody: "0|"},
+		{name: "wrong key", body: envelopeReply(t, client.StagePayment.Client(client.Stage), `{"RtnCode":3024}`), wantBody: "0|"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *StatusUpdate
+			handler := &StatusHandler{Client: c, OnStatus: func(_ context.Context, update *StatusUpdate) error {
+				got = update
+				return nil
+			}}
+
+			req := httptest.NewRequest(http.MethodPost, "/ecpay/logistics", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if !strings.HasPrefix(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want prefix %q", rec.Body.String(), tt.wantBody)
+			}
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("OnStatus called = %v, want %v", got != nil, tt.wantCalled)
+			}
+			if got != nil && (got.RtnCode != 3024 || got.ID() != "2401010001") {
+				t.Errorf("unexpected update %+v", got)
+			}
+		})
+	}
+}
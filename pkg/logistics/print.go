@@ -0,0 +1,140 @@
+package logistics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"io"
+	"strings"
+)
+
+// ErrNoShipments 列印託運單時沒有帶入任何物流訂單
+var ErrNoShipments = errors.New("no shipments to print")
+
+// Shipment identifies one logistics order to print. C2C waybills also need the
+// CVSPaymentNo, and 7-ELEVEN 交貨便 the CVSValidationNo, returned at creation.
+type Shipment struct {
+	// AllPayLogisticsID 綠界科技的物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID"`
+
+	// CVSPaymentNo 寄貨編號
+	CVSPaymentNo string `json:"CVSPaymentNo,omitempty"`
+
+	// CVSValidationNo 驗證碼
+	CVSValidationNo string `json:"CVSValidationNo,omitempty"`
+}
+
+// WaybillRequest prints the waybills of one or more orders of the same LogisticsSubType.
+// The endpoint is chosen by LogisticsSubType: printTradeDocument for B2C and home
+// delivery, the carrier's Print*C2COrderInfo for C2C.
+type WaybillRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType"`
+
+	// Shipments 要列印的物流訂單
+	Shipments []Shipment `json:"Shipments"`
+}
+
+// waybillForm 列印託運單表單參數, 多筆時以逗號分隔
+type waybillForm struct {
+	MerchantID        string `form:"MerchantID"`
+	AllPayLogisticsID string `form:"AllPayLogisticsID"`
+	CVSPaymentNo      string `form:"CVSPaymentNo"`
+	CVSValidationNo   string `form:"CVSValidationNo"`
+	PlatformID        string `form:"PlatformID"`
+}
+
+// endpoint returns the print API of the request's LogisticsSubType and whether the
+// C2C CVSPaymentNo / CVSValidationNo are sent. An empty or unknown subtype is an error
+// rather than a guess at printTradeDocument.
+func (r *WaybillRequest) endpoint() (api client.API, paymentNo bool, validationNo bool, err error) {
+	switch r.LogisticsSubType {
+	case SubTypeUNIMARTC2C:
+		return client.APILogisticsPrintUniMartC2C, true, true, nil
+	case SubTypeFAMIC2C:
+		return client.APILogisticsPrintFAMIC2C, true, false, nil
+	case SubTypeHILIFEC2C:
+		return client.APILogisticsPrintHILIFEC2C, true, false, nil
+	case SubTypeOKMARTC2C:
+		return client.APILogisticsPrintOKMARTC2C, true, false, nil
+	case SubTypeFAMI, SubTypeUNIMART, SubTypeUNIMARTFreeze, SubTypeHILIFE, SubTypeTCAT, SubTypePOST:
+		return client.APILogisticsPrintTradeDocument, false, false, nil
+	default:
+		return client.API{}, false, false, fmt.Errorf("列印託運單不支援 LogisticsSubType %q", r.LogisticsSubType)
+	}
+}
+
+func (r *WaybillRequest) form() (*waybillForm, client.API, error) {
+
+	if len(r.Shipments) == 0 {
+		return nil, client.API{}, ErrNoShipments
+	}
+
+	api, paymentNo, validationNo, err := r.endpoint()
+	if err != nil {
+		return nil, api, err
+	}
+
+	ids := make([]string, len(r.Shipments))
+	paymentNos := make([]string, len(r.Shipments))
+	validationNos := make([]string, len(r.Shipments))
+	for i, shipment := range r.Shipments {
+		if shipment.AllPayLogisticsID == "" {
+			return nil, api, fmt.Errorf("列印託運單 第 %d 筆缺少 AllPayLogisticsID", i+1)
+		}
+		if paymentNo && shipment.CVSPaymentNo == "" {
+			return nil, api, fmt.Errorf("列印託運單 %s 缺少 CVSPaymentNo", shipment.AllPayLogisticsID)
+		}
+		if validationNo && shipment.CVSValidationNo == "" {
+			return nil, api, fmt.Errorf("列印託運單 %s 缺少 CVSValidationNo", shipment.AllPayLogisticsID)
+		}
+		ids[i], paymentNos[i], validationNos[i] = shipment.AllPayLogisticsID, shipment.CVSPaymentNo, shipment.CVSValidationNo
+	}
+
+	form := &waybillForm{MerchantID: r.MerchantID, AllPayLogisticsID: strings.Join(ids, ","), PlatformID: r.PlatformID}
+	if paymentNo {
+		form.CVSPaymentNo = strings.Join(paymentNos, ",")
+	}
+	if validationNo {
+		form.CVSValidationNo = strings.Join(validationNos, ",")
+	}
+
+	return form, api, nil
+}
+
+// Print 列印託運單. Waybills are small pages, so the HTML (or PDF) is read in full with the
+// client's normal Doer and DefaultTimeout; the returned reader needs no more network access.
+func (r *WaybillRequest) Print() (io.ReadCloser, error) {
+	return r.PrintContext(context.Background())
+}
+
+// PrintContext is like Print but carries ctx to the outgoing request.
+func (r *WaybillRequest) PrintContext(ctx context.Context) (io.ReadCloser, error) {
+
+	form, api, err := r.form()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := sendSigned(ctx, r.Client, api, form)
+	if err != nil {
+		return nil, err
+	}
+
+	// 失敗時綠界以 "0|錯誤訊息" 回應, 而非託運單頁面
+	if bytes.HasPrefix(body, []byte("0|")) {
+		return nil, fmt.Errorf("列印託運單失敗 失敗原因 : %s", strings.TrimPrefix(string(body), "0|"))
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
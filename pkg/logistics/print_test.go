@@ -0,0 +1,114 @@
+package logistics
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWaybillRequestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		request WaybillRequest
+		wantErr error
+	}{
+		{name: "no shipments", request: WaybillRequest{LogisticsSubType: SubTypeTCAT}, wantErr: ErrNoShipments},
+		{name: "no subtype", request: WaybillRequest{Shipments: []Shipment{{AllPayLogisticsID: "1"}}}},
+		{name: "unknown subtype", request: WaybillRequest{LogisticsSubType: "ECAN", Shipments: []Shipment{{AllPayLogisticsID: "1"}}}},
+		{name: "missing id", request: WaybillRequest{LogisticsSubType: SubTypeTCAT, Shipments: []Shipment{{AllPayLogisticsID: "1"}, {}}}},
+		{name: "c2c without payment no", request: WaybillRequest{LogisticsSubType: SubTypeFAMIC2C, Shipments: []Shipment{{AllPayLogisticsID: "1"}}}},
+		{
+			name:    "unimart c2c without validation no",
+			request: WaybillRequest{LogisticsSubType: SubTypeUNIMARTC2C, Shipments: []Shipment{{AllPayLogisticsID: "1", CVSPaymentNo: "P1"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &replyDoer{reply: "<html></html>"}
+			tt.request.Client = testClient(doer)
+
+			_, err := tt.request.Print()
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("Print() error = %v, want %v", err, tt.wantErr)
+			}
+			if doer.calls != 0 {
+				t.Error("an invalid request was sent")
+			}
+		})
+	}
+}
+
+func TestWaybillRequestPrint(t *testing.T) {
+	tests := []struct {
+		name             string
+		subType          string
+		shipments        []Shipment
+		wantPath         string
+		wantPaymentNo    string
+		wantValidationNo string
+	}{
+		{
+			name:      "home delivery",
+			subType:   SubTypeTCAT,
+			shipments: []Shipment{{AllPayLogisticsID: "1", CVSPaymentNo: "ignored"}, {AllPayLogisticsID: "2"}},
+			wantPath:  "/helper/printTradeDocument",
+		},
+		{
+			name:             "unimart c2c",
+			subType:          SubTypeUNIMARTC2C,
+			shipments:        []Shipment{{AllPayLogisticsID: "1", CVSPaymentNo: "P1", CVSValidationNo: "V1"}, {AllPayLogisticsID: "2", CVSPaymentNo: "P2", CVSValidationNo: "V2"}},
+			wantPath:         "/Express/PrintUniMartC2COrderInfo",
+			wantPaymentNo:    "P1,P2",
+			wantValidationNo: "V1,V2",
+		},
+		{
+			name:          "fami c2c",
+			subType:       SubTypeFAMIC2C,
+			shipments:     []Shipment{{AllPayLogisticsID: "1", CVSPaymentNo: "P1", CVSValidationNo: "ignored"}, {AllPayLogisticsID: "2", CVSPaymentNo: "P2"}},
+			wantPath:      "/Express/PrintFAMIC2COrderInfo",
+			wantPaymentNo: "P1,P2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &replyDoer{reply: "<html>waybill</html>"}
+			r := &WaybillRequest{Client: testClient(doer), LogisticsSubType: tt.subType, Shipments: tt.shipments}
+
+			page, err := r.Print()
+			if err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+			defer page.Close()
+			if content, _ := io.ReadAll(page); string(content) != doer.reply {
+				t.Errorf("page = %q", content)
+			}
+
+			if !strings.HasSuffix(doer.url, tt.wantPath) {
+				t.Errorf("sent to %s, want %s", doer.url, tt.wantPath)
+			}
+			form := doer.form(t)
+			if form.Get("AllPayLogisticsID") != "1,2" || form.Get("CVSPaymentNo") != tt.wantPaymentNo ||
+				form.Get("CVSValidationNo") != tt.wantValidationNo || form.Get("MerchantID") != r.Client.MerchantID {
+				t.Errorf("sent %v", form)
+			}
+			if err = validation.ValidateCheckMacValue(form, r.Client.HashKey, r.Client.HashIV,
+				helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+				t.Errorf("request is not signed with MD5: %v", err)
+			}
+		})
+	}
+}
+
+func TestWaybillRequestPrintFailure(t *testing.T) {
+	doer := &replyDoer{reply: "0|查無此筆物流訂單"}
+	r := &WaybillRequest{Client: testClient(doer), LogisticsSubType: SubTypeTCAT, Shipments: []Shipment{{AllPayLogisticsID: "1"}}}
+
+	if _, err := r.Print(); err == nil || !strings.Contains(err.Error(), "查無此筆物流訂單") {
+		t.Errorf("Print() error = %v, want ECPay's message", err)
+	}
+}
@@ -0,0 +1,341 @@
+package logistics
+
+import (
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"strconv"
+	"strings"
+)
+
+// ReturnResult is the reverse logistics order ECPay issues for a B2C CVS return
+type ReturnResult struct {
+	// RtnMerchantTradeNo 退貨的特店交易編號
+	RtnMerchantTradeNo string `json:"RtnMerchantTradeNo"`
+
+	// RtnOrderNo 退貨編號, 消費者至門市寄件時使用
+	RtnOrderNo string `json:"RtnOrderNo"`
+}
+
+// parseReturnResult parses the "RtnMerchantTradeNo|RtnOrderNo" reply; failures come back as "0|message".
+func parseReturnResult(body []byte) (*ReturnResult, error) {
+
+	first, second, found := strings.Cut(strings.TrimSpace(string(body)), "|")
+	if !found || first == "0" || first == "" {
+		return nil, fmt.Errorf("建立逆物流訂單失敗 失敗原因 : %s", second)
+	}
+
+	return &ReturnResult{RtnMerchantTradeNo: first, RtnOrderNo: second}, nil
+}
+
+// ReturnCVSRequest is a B2C CVS return (全家, 7-ELEVEN, 萊爾富): the buyer drops the parcel
+// at any store of the carrier and it is shipped back to the merchant's warehouse.
+type ReturnCVSRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 原物流訂單的綠界物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID,omitempty" form:"AllPayLogisticsID"`
+
+	// LogisticsSubType 原物流訂單的物流子類型 (FAMI, UNIMART, HILIFE), 決定呼叫的 API, 不會送出
+	LogisticsSubType string `json:"LogisticsSubType"`
+
+	// ServerReplyURL 物流狀態通知網址
+	ServerReplyURL string `json:"ServerReplyURL" form:"ServerReplyURL"`
+
+	// Goods 退貨商品資訊
+	model.Goods `json:",inline"`
+
+	// CollectionAmount 代收金額, 退貨為 0, 一律送出 (包含 0)
+	CollectionAmount int `json:"CollectionAmount" form:"CollectionAmount"`
+
+	// ServiceType 服務型態, 固定帶 4 (退貨不付款)
+	ServiceType string `json:"ServiceType" form:"ServiceType"`
+
+	// Sender 退貨人資訊, 超商逆物流僅送出 SenderName 與 SenderPhone
+	model.Sender `json:",inline"`
+
+	// Remark 備註
+	Remark string `json:"Remark,omitempty" form:"Remark"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// Return 建立超商逆物流訂單
+func (r *ReturnCVSRequest) Return() (*ReturnResult, error) {
+	return r.ReturnContext(context.Background())
+}
+
+// ReturnContext is like Return but carries ctx to the outgoing request.
+func (r *ReturnCVSRequest) ReturnContext(ctx context.Context) (*ReturnResult, error) {
+
+	if IsC2C(r.LogisticsSubType) {
+		return nil, fmt.Errorf("店到店訂單請使用 C2CReturnRequest")
+	}
+
+	var api client.API
+	switch CarrierOf(r.LogisticsSubType) {
+	case CarrierFAMI:
+		api = client.APILogisticsReturnFAMI
+	case CarrierUNIMART:
+		api = client.APILogisticsReturnUniMart
+	case CarrierHILIFE:
+		api = client.APILogisticsReturnHiLife
+	default:
+		return nil, fmt.Errorf("物流子類型 %s 不支援超商逆物流", r.LogisticsSubType)
+	}
+
+	form := r.form()
+	if form.ServiceType == "" {
+		form.ServiceType = "4"
+	}
+
+	body, err := sendSigned(ctx, r.Client, api, form)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReturnResult(body)
+}
+
+// returnCVSForm 超商逆物流表單參數. CollectionAmount 以字串送出, 金額為 0 時也不會被省略.
+type returnCVSForm struct {
+	MerchantID        string `form:"MerchantID"`
+	AllPayLogisticsID string `form:"AllPayLogisticsID"`
+	ServerReplyURL    string `form:"ServerReplyURL"`
+	GoodsName         string `form:"GoodsName"`
+	GoodsAmount       int    `form:"GoodsAmount"`
+	CollectionAmount  string `form:"CollectionAmount"`
+	ServiceType       string `form:"ServiceType"`
+	SenderName        string `form:"SenderName"`
+	SenderPhone       string `form:"SenderPhone"`
+	Remark            string `form:"Remark"`
+	PlatformID        string `form:"PlatformID"`
+}
+
+// form returns exactly the parameters the 全家 / 7-ELEVEN / 萊爾富 return APIs accept;
+// SenderCellPhone, SenderZipCode and SenderAddress are not part of them.
+func (r *ReturnCVSRequest) form() *returnCVSForm {
+	return &returnCVSForm{
+		MerchantID:        r.MerchantID,
+		AllPayLogisticsID: r.AllPayLogisticsID,
+		ServerReplyURL:    r.ServerReplyURL,
+		GoodsName:         r.GoodsName,
+		GoodsAmount:       r.GoodsAmount,
+		CollectionAmount:  strconv.Itoa(r.CollectionAmount),
+		ServiceType:       r.ServiceType,
+		SenderName:        r.SenderName,
+		SenderPhone:       r.SenderPhone,
+		Remark:            r.Remark,
+		PlatformID:        r.PlatformID,
+	}
+}
+
+// ReturnHomeRequest is a home delivery return (黑貓, 郵局): the carrier picks the parcel
+// up from Sender and delivers it to Receiver. Either AllPayLogisticsID of the original
+// order or the full sender and receiver information is required.
+type ReturnHomeRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID" form:"MerchantID"`
+
+	// AllPayLogisticsID 原物流訂單的綠界物流交易編號
+	AllPayLogisticsID string `json:"AllPayLogisticsID,omitempty" form:"AllPayLogisticsID"`
+
+	// LogisticsSubType 物流子類型 (TCAT, POST)
+	LogisticsSubType string `json:"LogisticsSubType,omitempty" form:"LogisticsSubType"`
+
+	// ServerReplyURL 物流狀態通知網址
+	ServerReplyURL string `json:"ServerReplyURL" form:"ServerReplyURL"`
+
+	// Sender 退貨人 (取件) 資訊
+	model.Sender `json:",inline"`
+
+	// Receiver 退貨收件人資訊, 通常為特店倉庫
+	model.Receiver `json:",inline"`
+
+	// Goods 退貨商品資訊
+	model.Goods `json:",inline"`
+
+	// Temperature 溫層
+	Temperature string `json:"Temperature,omitempty" form:"Temperature"`
+
+	// Distance 距離
+	Distance string `json:"Distance,omitempty" form:"Distance"`
+
+	// Specification 規格
+	Specification string `json:"Specification,omitempty" form:"Specification"`
+
+	// ScheduledPickupTime 預定取件時段
+	ScheduledPickupTime string `json:"ScheduledPickupTime,omitempty" form:"ScheduledPickupTime"`
+
+	// ScheduledDeliveryTime 預定送達時段
+	ScheduledDeliveryTime string `json:"ScheduledDeliveryTime,omitempty" form:"ScheduledDeliveryTime"`
+
+	// Remark 備註
+	Remark string `json:"Remark,omitempty" form:"Remark"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// Validate checks the return against the home delivery rules before it is sent.
+// The returned error wraps ErrInvalidHomeOrder.
+func (r *ReturnHomeRequest) Validate() error {
+
+	if r.AllPayLogisticsID == "" {
+		if r.LogisticsSubType != SubTypeTCAT && r.LogisticsSubType != SubTypePOST {
+			return invalidHomeOrder("未帶 AllPayLogisticsID 時, 物流子類型須為 TCAT 或 POST, 收到 %q", r.LogisticsSubType)
+		}
+		if r.SenderName == "" || r.SenderPhone == "" && r.SenderCellPhone == "" || r.SenderZipCode == "" || r.SenderAddress == "" {
+			return invalidHomeOrder("未帶 AllPayLogisticsID 時, 須提供退貨人姓名, 電話, 郵遞區號與地址")
+		}
+		if r.ReceiverName == "" || r.ReceiverPhone == "" && r.ReceiverCellPhone == "" || r.ReceiverZipCode == "" || r.ReceiverAddress == "" {
+			return invalidHomeOrder("未帶 AllPayLogisticsID 時, 須提供退貨收件人姓名, 電話, 郵遞區號與地址")
+		}
+	} else if r.LogisticsSubType != "" && r.LogisticsSubType != SubTypeTCAT && r.LogisticsSubType != SubTypePOST {
+		return invalidHomeOrder("宅配物流子類型須為 TCAT 或 POST, 收到 %q", r.LogisticsSubType)
+	}
+	if r.GoodsAmount <= 0 {
+		return invalidHomeOrder("GoodsAmount 須大於 0")
+	}
+	if r.ServerReplyURL == "" {
+		return invalidHomeOrder("缺少 ServerReplyURL")
+	}
+
+	return validateHomeOptions(r.Temperature, r.Specification, r.Distance, r.ScheduledPickupTime, r.ScheduledDeliveryTime)
+}
+
+// Return 建立宅配逆物流訂單
+func (r *ReturnHomeRequest) Return() error {
+	return r.ReturnContext(context.Background())
+}
+
+// ReturnContext is like Return but carries ctx to the outgoing request.
+func (r *ReturnHomeRequest) ReturnContext(ctx context.Context) error {
+
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	body, err := sendSigned(ctx, r.Client, client.APILogisticsReturnHome, r)
+	if err != nil {
+		return err
+	}
+
+	return expectOK(body, "建立宅配逆物流訂單")
+}
+
+// C2CReturnRequest returns a 店到店 (C2C) parcel. ECPay has no return API for C2C, so
+// the return is a new C2C order from the buyer's store back to the seller's store.
+type C2CReturnRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// Merchant 特店資訊, MerchantTradeNo 須為新的退貨訂單編號
+	model.Merchant `json:",inline"`
+
+	// LogisticsType 物流類型, 固定為 CVS
+	LogisticsType string `json:"LogisticsType" form:"LogisticsType"`
+
+	// LogisticsSubType 物流子類型 (UNIMARTC2C, FAMIC2C, HILIFEC2C, OKMARTC2C)
+	LogisticsSubType string `json:"LogisticsSubType" form:"LogisticsSubType"`
+
+	// Goods 退貨商品資訊
+	model.Goods `json:",inline"`
+
+	// IsCollection 是否代收貨款, 退貨固定為 N
+	IsCollection string `json:"IsCollection" form:"IsCollection"`
+
+	// Sender 退貨人 (原收件人) 資訊, 店到店不送出 SenderZipCode 與 SenderAddress
+	model.Sender `json:",inline"`
+
+	// Receiver 退貨收件人 (原寄件人) 資訊, ReceiverStoreID 為收退貨的門市
+	model.Receiver `json:",inline"`
+
+	// ServerReplyURL 物流狀態通知網址
+	ServerReplyURL string `json:"ServerReplyURL" form:"ServerReplyURL"`
+
+	// Remark 備註
+	Remark string `json:"Remark,omitempty" form:"Remark"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+}
+
+// C2COriginalOrder is the 店到店 shipment being returned, from which NewC2CReturn builds the return order
+type C2COriginalOrder struct {
+	Client *client.ECPayClient
+
+	// Merchant 退貨訂單的特店資訊, MerchantTradeNo 須為新的退貨訂單編號
+	Merchant model.Merchant
+
+	// LogisticsSubType 原物流訂單的物流子類型 (UNIMARTC2C, FAMIC2C, HILIFEC2C, OKMARTC2C)
+	LogisticsSubType string
+
+	// Goods 退貨商品資訊
+	Goods model.Goods
+
+	// Sender 原寄件人 (賣家), 成為退貨收件人
+	Sender model.Sender
+
+	// Receiver 原收件人 (買家), 成為退貨人
+	Receiver model.Receiver
+
+	// ReturnStoreID 賣家收退貨的門市代號
+	ReturnStoreID string
+}
+
+// NewC2CReturn builds the return of a C2C shipment from its original parties: the
+// original receiver becomes the sender and the original sender receives the parcel
+// at ReturnStoreID.
+func NewC2CReturn(original C2COriginalOrder) *C2CReturnRequest {
+	return &C2CReturnRequest{
+		Client:           original.Client,
+		Merchant:         original.Merchant,
+		LogisticsSubType: original.LogisticsSubType,
+		Goods:            original.Goods,
+		Sender: model.Sender{
+			SenderName:      original.Receiver.ReceiverName,
+			SenderPhone:     original.Receiver.ReceiverPhone,
+			SenderCellPhone: original.Receiver.ReceiverCellPhone,
+		},
+		Receiver: model.Receiver{
+			ReceiverName:      original.Sender.SenderName,
+			ReceiverPhone:     original.Sender.SenderPhone,
+			ReceiverCellPhone: original.Sender.SenderCellPhone,
+			ReceiverStoreID:   original.ReturnStoreID,
+		},
+	}
+}
+
+// Return 建立店到店退貨訂單
+func (r *C2CReturnRequest) Return() (*CreateResult, error) {
+	return r.ReturnContext(context.Background())
+}
+
+// ReturnContext is like Return but carries ctx to the outgoing request.
+func (r *C2CReturnRequest) ReturnContext(ctx context.Context) (*CreateResult, error) {
+
+	if !IsC2C(r.LogisticsSubType) {
+		return nil, fmt.Errorf("物流子類型 %s 不是店到店", r.LogisticsSubType)
+	}
+	if r.ReceiverStoreID == "" {
+		return nil, fmt.Errorf("店到店退貨缺少收退貨門市 ReceiverStoreID")
+	}
+
+	// 在複本上補齊固定欄位, 不修改呼叫端的請求
+	request := *r
+	request.LogisticsType = LogisticsTypeCVS
+	request.IsCollection = "N"
+	request.MerchantTradeDate = tradeDate(r.MerchantTradeDate)
+
+	// 寄件人郵遞區號與地址僅宅配使用
+	request.SenderZipCode, request.SenderAddress = "", ""
+
+	return createOrder(ctx, r.Client, &request)
+}
@@ -0,0 +1,204 @@
+package logistics
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// createReply signs values into the "1|key=value&..." reply of Express/Create.
+func createReply(c *client.ECPayClient, values url.Values) string {
+	values.Set("CheckMacValue", helpers.GenerateCheckMacValue(values, c.HashKey, c.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)))
+	return "1|" + values.Encode()
+}
+
+func TestReturnCVSRequest(t *testing.T) {
+	doer := &replyDoer{reply: "R0001|12345678"}
+	r := &ReturnCVSRequest{
+		Client:            testClient(doer),
+		MerchantID:        "2000132",
+		AllPayLogisticsID: "1718",
+		LogisticsSubType:  SubTypeFAMI,
+		ServerReplyURL:    "https://example.com/reply",
+		Goods:             model.Goods{GoodsName: "書", GoodsAmount: 100},
+		Sender: model.Sender{
+			SenderName:      "王小明",
+			SenderPhone:     "0212345678",
+			SenderCellPhone: "0912345678",
+			SenderZipCode:   "100",
+			SenderAddress:   "台北市中正區",
+		},
+	}
+
+	result, err := r.Return()
+	if err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+	if result.RtnMerchantTradeNo != "R0001" || result.RtnOrderNo != "12345678" {
+		t.Errorf("Return() = %+v", result)
+	}
+	if r.ServiceType != "" {
+		t.Errorf("Return() changed the request's ServiceType to %q", r.ServiceType)
+	}
+
+	form := doer.form(t)
+	if !strings.HasSuffix(doer.url, client.APILogisticsReturnFAMI.Path) {
+		t.Errorf("sent to %s", doer.url)
+	}
+	if form.Get("ServiceType") != "4" || form.Get("CollectionAmount") != "0" {
+		t.Errorf("ServiceType = %q, CollectionAmount = %q; want 4 and 0", form.Get("ServiceType"), form.Get("CollectionAmount"))
+	}
+	for _, key := range []string{"SenderCellPhone", "SenderZipCode", "SenderAddress", "LogisticsSubType"} {
+		if form.Has(key) {
+			t.Errorf("%s was sent", key)
+		}
+	}
+	if err = validation.ValidateCheckMacValue(form, r.Client.HashKey, r.Client.HashIV, helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+		t.Errorf("form is not MD5 signed: %v", err)
+	}
+
+	doer.reply = "0|找不到物流訂單"
+	if _, err = r.Return(); err == nil {
+		t.Error("Return() accepted a 0| reply")
+	}
+
+	for _, subType := range []string{SubTypeFAMIC2C, SubTypeTCAT} {
+		doer.calls = 0
+		r.LogisticsSubType = subType
+		if _, err = r.Return(); err == nil || doer.calls != 0 {
+			t.Errorf("Return() with %s: error = %v, calls = %d", subType, err, doer.calls)
+		}
+	}
+}
+
+func validReturnHome() ReturnHomeRequest {
+	return ReturnHomeRequest{
+		MerchantID:       "2000132",
+		LogisticsSubType: SubTypeTCAT,
+		ServerReplyURL:   "https://example.com/reply",
+		Sender:           model.Sender{SenderName: "王小明", SenderCellPhone: "0912345678", SenderZipCode: "100", SenderAddress: "台北市中正區"},
+		Receiver:         model.Receiver{ReceiverName: "倉庫", ReceiverPhone: "0212345678", ReceiverZipCode: "114", ReceiverAddress: "台北市內湖區"},
+		Goods:            model.Goods{GoodsName: "書", GoodsAmount: 100},
+	}
+}
+
+func TestReturnHomeRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(r *ReturnHomeRequest)
+		wantErr bool
+	}{
+		{name: "full information", modify: func(r *ReturnHomeRequest) {}},
+		{
+			name: "original order only",
+			modify: func(r *ReturnHomeRequest) {
+				r.AllPayLogisticsID, r.LogisticsSubType, r.Sender, r.Receiver = "1718", "", model.Sender{}, model.Receiver{}
+			},
+		},
+		{name: "no subtype without original order", modify: func(r *ReturnHomeRequest) { r.LogisticsSubType = "" }, wantErr: true},
+		{name: "cvs subtype", modify: func(r *ReturnHomeRequest) { r.AllPayLogisticsID, r.LogisticsSubType = "1718", SubTypeFAMI }, wantErr: true},
+		{name: "no sender phone", modify: func(r *ReturnHomeRequest) { r.SenderCellPhone = "" }, wantErr: true},
+		{name: "no sender address", modify: func(r *ReturnHomeRequest) { r.SenderAddress = "" }, wantErr: true},
+		{name: "no receiver name", modify: func(r *ReturnHomeRequest) { r.ReceiverName = "" }, wantErr: true},
+		{name: "no receiver zip code", modify: func(r *ReturnHomeRequest) { r.ReceiverZipCode = "" }, wantErr: true},
+		{name: "no goods amount", modify: func(r *ReturnHomeRequest) { r.GoodsAmount = 0 }, wantErr: true},
+		{name: "no reply url", modify: func(r *ReturnHomeRequest) { r.ServerReplyURL = "" }, wantErr: true},
+		{name: "unknown temperature", modify: func(r *ReturnHomeRequest) { r.Temperature = "0004" }, wantErr: true},
+		{name: "unknown pickup time", modify: func(r *ReturnHomeRequest) { r.ScheduledPickupTime = "5" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validReturnHome()
+			tt.modify(&r)
+
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidHomeOrder) {
+				t.Errorf("Validate() error = %v, want it to wrap ErrInvalidHomeOrder", err)
+			}
+		})
+	}
+}
+
+func TestReturnHomeRequestReturn(t *testing.T) {
+	doer := &replyDoer{reply: "1|OK"}
+	r := validReturnHome()
+	r.Client = testClient(doer)
+
+	if err := r.Return(); err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+	form := doer.form(t)
+	if !strings.HasSuffix(doer.url, client.APILogisticsReturnHome.Path) || form.Get("SenderZipCode") != "100" || form.Get("ReceiverAddress") != "台北市內湖區" {
+		t.Errorf("sent %s to %s", doer.body, doer.url)
+	}
+
+	doer.calls = 0
+	r.GoodsAmount = 0
+	if err := r.Return(); !errors.Is(err, ErrInvalidHomeOrder) || doer.calls != 0 {
+		t.Errorf("Return() error = %v, calls = %d; want an unsent invalid return", err, doer.calls)
+	}
+}
+
+func TestC2CReturnRequest(t *testing.T) {
+	doer := &replyDoer{}
+	r := NewC2CReturn(C2COriginalOrder{
+		Client:           testClient(doer),
+		Merchant:         model.Merchant{MerchantID: "2000132", MerchantTradeNo: "R0001"},
+		LogisticsSubType: SubTypeFAMIC2C,
+		Goods:            model.Goods{GoodsName: "書", GoodsAmount: 100},
+		Sender:           model.Sender{SenderName: "賣家", SenderCellPhone: "0911111111", SenderZipCode: "100", SenderAddress: "台北市中正區"},
+		Receiver:         model.Receiver{ReceiverName: "買家", ReceiverCellPhone: "0922222222"},
+		ReturnStoreID:    "006598",
+	})
+	r.SenderZipCode, r.SenderAddress = "114", "台北市內湖區"
+	doer.reply = createReply(r.Client, url.Values{
+		"MerchantID":        {"2000132"},
+		"MerchantTradeNo":   {"R0001"},
+		"RtnCode":           {"300"},
+		"AllPayLogisticsID": {"1719"},
+		"CVSPaymentNo":      {"F0001"},
+	})
+
+	result, err := r.Return()
+	if err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+	if result.AllPayLogisticsID != "1719" || result.CVSPaymentNo != "F0001" {
+		t.Errorf("Return() = %+v", result)
+	}
+	if r.LogisticsType != "" || r.IsCollection != "" || r.MerchantTradeDate != "" || r.SenderZipCode != "114" {
+		t.Errorf("Return() changed the request: %+v", r)
+	}
+
+	form := doer.form(t)
+	if form.Get("LogisticsType") != LogisticsTypeCVS || form.Get("IsCollection") != "N" || form.Get("MerchantTradeDate") == "" {
+		t.Errorf("sent %s", doer.body)
+	}
+	if form.Get("SenderName") != "買家" || form.Get("ReceiverName") != "賣家" || form.Get("ReceiverStoreID") != "006598" {
+		t.Errorf("parties were not swapped: %s", doer.body)
+	}
+	if form.Get("SenderZipCode") != "" || form.Get("SenderAddress") != "" {
+		t.Errorf("sender address was sent: %s", doer.body)
+	}
+
+	for name, modify := range map[string]func(r *C2CReturnRequest){
+		"not c2c":  func(r *C2CReturnRequest) { r.LogisticsSubType = SubTypeFAMI },
+		"no store": func(r *C2CReturnRequest) { r.ReceiverStoreID = "" },
+	} {
+		doer.calls = 0
+		invalid := *r
+		modify(&invalid)
+		if _, err = invalid.Return(); err == nil || doer.calls != 0 {
+			t.Errorf("%s: Return() error = %v, calls = %d", name, err, doer.calls)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package logistics
+
+import (
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ./internal/statusgen -in status_codes.tsv -out status_codes_gen.go
+
+// Carrier 物流業者, 狀態代碼依業者而有不同意義
+type Carrier string
+
+const (
+	// CarrierUNIMART 7-ELEVEN
+	CarrierUNIMART Carrier = "UNIMART"
+
+	// CarrierFAMI 全家
+	CarrierFAMI Carrier = "FAMI"
+
+	// CarrierHILIFE 萊爾富
+	CarrierHILIFE Carrier = "HILIFE"
+
+	// CarrierOKMART OK超商
+	CarrierOKMART Carrier = "OKMART"
+
+	// CarrierTCAT 黑貓宅急便
+	CarrierTCAT Carrier = "TCAT"
+
+	// CarrierPOST 中華郵政
+	CarrierPOST Carrier = "POST"
+
+	// carrierAny 所有業者共用的代碼
+	carrierAny Carrier = "*"
+)
+
+// CarrierOf returns the carrier of a LogisticsSubType such as UNIMARTC2C or UNIMARTFREEZE.
+func CarrierOf(subType string) Carrier {
+	subType = strings.ToUpper(subType)
+	for _, carrier := range []Carrier{CarrierUNIMART, CarrierFAMI, CarrierHILIFE, CarrierOKMART, CarrierTCAT, CarrierPOST} {
+		if strings.HasPrefix(subType, string(carrier)) {
+			return carrier
+		}
+	}
+	return Carrier(subType)
+}
+
+// State 正規化後的物流狀態
+type State int
+
+const (
+	// StateUnknown 未收錄的狀態代碼
+	StateUnknown State = iota
+
+	// StateCreated 訂單已建立, 尚未交寄
+	StateCreated
+
+	// StateInTransit 運送中
+	StateInTransit
+
+	// StateArrivedAtStore 已到取貨門市 / 營業所, 等待取貨
+	StateArrivedAtStore
+
+	// StatePickedUp 消費者已取貨
+	StatePickedUp
+
+	// StateDelivered 宅配已送達
+	StateDelivered
+
+	// StateException 配送異常 (不在家, 地址錯誤...), 業者會再處理
+	StateException
+
+	// StateReturning 退貨中
+	StateReturning
+
+	// StateReturned 已退回寄件人
+	StateReturned
+
+	// StateLost 遺失或損壞
+	StateLost
+
+	// StateFailed 訂單處理失敗
+	StateFailed
+)
+
+var stateNames = [...]string{
+	StateUnknown:        "Unknown",
+	StateCreated:        "Created",
+	StateInTransit:      "InTransit",
+	StateArrivedAtStore: "ArrivedAtStore",
+	StatePickedUp:       "PickedUp",
+	StateDelivered:      "Delivered",
+	StateException:      "Exception",
+	StateReturning:      "Returning",
+	StateReturned:       "Returned",
+	StateLost:           "Lost",
+	StateFailed:         "Failed",
+}
+
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+	return stateNames[s]
+}
+
+// MarshalText encodes the state by name.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// IsFinal reports whether no further status change is expected.
+func (s State) IsFinal() bool {
+	switch s {
+	case StatePickedUp, StateDelivered, StateReturned, StateLost, StateFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status describes one logistics status code
+type Status struct {
+	// Carrier 物流業者, 共用代碼為 "*"
+	Carrier Carrier `json:"Carrier"`
+
+	// Code 狀態代碼
+	Code int `json:"Code"`
+
+	// State 正規化狀態
+	State State `json:"State"`
+
+	// Chinese 中文說明
+	Chinese string `json:"Chinese"`
+
+	// English 英文說明
+	English string `json:"English"`
+}
+
+type statusKey struct {
+	carrier Carrier
+	code    int
+}
+
+// LookupStatus returns the description of code for the carrier of subType (see CarrierOf).
+// Codes shared by every carrier are used when the carrier has no specific entry.
+func LookupStatus(subType string, code int) (Status, bool) {
+
+	carrier := CarrierOf(subType)
+	if status, ok := statusCatalogue[statusKey{carrier, code}]; ok {
+		return status, true
+	}
+	if status, ok := statusCatalogue[statusKey{carrierAny, code}]; ok {
+		return status, true
+	}
+
+	return Status{Carrier: carrier, Code: code, State: StateUnknown}, false
+}
+
+// Status returns the catalogue entry of the update's RtnCode.
+func (s *StatusUpdate) Status() (Status, bool) {
+	return LookupStatus(s.LogisticsSubType, s.RtnCode)
+}
+
+// Status returns the catalogue entry of the order's LogisticsStatus.
+func (o *LogisticsOrder) Status() (Status, bool) {
+	code, err := strconv.Atoi(o.LogisticsStatus)
+	if err != nil {
+		return Status{Carrier: CarrierOf(o.LogisticsSubType), State: StateUnknown}, false
+	}
+	return LookupStatus(o.LogisticsSubType, code)
+}
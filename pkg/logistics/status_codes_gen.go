@@ -0,0 +1,76 @@
+// Code generated by statusgen from status_codes.tsv; DO NOT EDIT.
+
+package logistics
+
+var statusCatalogue = map[statusKey]Status{
+	{carrierAny, 300}:      {Carrier: carrierAny, Code: 300, State: StateCreated, Chinese: "訂單處理中(已收到訂單資料)", English: "Order received"},
+	{carrierAny, 310}:      {Carrier: carrierAny, Code: 310, State: StateCreated, Chinese: "上傳電子訂單檔處理中", English: "Uploading order file"},
+	{carrierAny, 311}:      {Carrier: carrierAny, Code: 311, State: StateReturning, Chinese: "上傳退貨電子訂單處理中", English: "Uploading return order file"},
+	{carrierAny, 325}:      {Carrier: carrierAny, Code: 325, State: StateReturning, Chinese: "退貨訂單處理中(已收到訂單資料)", English: "Return order received"},
+	{CarrierUNIMART, 2001}: {Carrier: CarrierUNIMART, Code: 2001, State: StateCreated, Chinese: "檔案傳送成功", English: "Order file transmitted to carrier"},
+	{CarrierUNIMART, 2002}: {Carrier: CarrierUNIMART, Code: 2002, State: StateFailed, Chinese: "檔案傳送失敗", English: "Order file transmission failed"},
+	{CarrierUNIMART, 2003}: {Carrier: CarrierUNIMART, Code: 2003, State: StateFailed, Chinese: "無此路線", English: "Route does not exist"},
+	{CarrierUNIMART, 2030}: {Carrier: CarrierUNIMART, Code: 2030, State: StateInTransit, Chinese: "物流中心驗收成功", English: "Accepted at distribution center"},
+	{CarrierUNIMART, 2031}: {Carrier: CarrierUNIMART, Code: 2031, State: StateException, Chinese: "未到貨(物流端未收到該商品)", English: "Not received by distribution center"},
+	{CarrierUNIMART, 2063}: {Carrier: CarrierUNIMART, Code: 2063, State: StateArrivedAtStore, Chinese: "門市配達", English: "Arrived at pickup store"},
+	{CarrierUNIMART, 2065}: {Carrier: CarrierUNIMART, Code: 2065, State: StateReturned, Chinese: "EC收退", English: "Returned to merchant"},
+	{CarrierUNIMART, 2066}: {Carrier: CarrierUNIMART, Code: 2066, State: StateLost, Chinese: "異常收退(商品遺失或損壞)", English: "Lost or damaged in transit"},
+	{CarrierUNIMART, 2067}: {Carrier: CarrierUNIMART, Code: 2067, State: StatePickedUp, Chinese: "消費者成功取件", English: "Picked up by customer"},
+	{CarrierUNIMART, 2068}: {Carrier: CarrierUNIMART, Code: 2068, State: StateInTransit, Chinese: "交貨便收件(寄件門市收件)", English: "Received at sender store"},
+	{CarrierUNIMART, 2069}: {Carrier: CarrierUNIMART, Code: 2069, State: StateReturning, Chinese: "退貨便收件(商品退回指定C門市)", English: "Return received at store"},
+	{CarrierUNIMART, 2070}: {Carrier: CarrierUNIMART, Code: 2070, State: StateReturned, Chinese: "退回原寄件門市且已取件", English: "Returned to sender store and collected"},
+	{CarrierUNIMART, 2072}: {Carrier: CarrierUNIMART, Code: 2072, State: StateReturning, Chinese: "商品配達賣家取退貨門市", English: "Return arrived at seller's store"},
+	{CarrierUNIMART, 2073}: {Carrier: CarrierUNIMART, Code: 2073, State: StateArrivedAtStore, Chinese: "商品配達買家取貨門市", English: "Arrived at buyer's pickup store"},
+	{CarrierUNIMART, 2074}: {Carrier: CarrierUNIMART, Code: 2074, State: StateReturning, Chinese: "消費者七天未取,商品離開門市", English: "Not picked up within 7 days, left store"},
+	{CarrierUNIMART, 2075}: {Carrier: CarrierUNIMART, Code: 2075, State: StateReturning, Chinese: "廠商未至門市取退貨,商品離開門市", English: "Return not collected by seller, left store"},
+	{CarrierUNIMART, 2076}: {Carrier: CarrierUNIMART, Code: 2076, State: StateReturning, Chinese: "消費者七天未取,商品退回至大智通", English: "Not picked up within 7 days, returned to distribution center"},
+	{CarrierUNIMART, 2078}: {Carrier: CarrierUNIMART, Code: 2078, State: StateReturning, Chinese: "買家未取貨退回物流中心-驗收成功", English: "Uncollected parcel accepted back at distribution center"},
+	{CarrierUNIMART, 2101}: {Carrier: CarrierUNIMART, Code: 2101, State: StateInTransit, Chinese: "門市關轉店", English: "Pickup store closed, rerouted"},
+	{CarrierUNIMART, 2102}: {Carrier: CarrierUNIMART, Code: 2102, State: StateInTransit, Chinese: "門市舊店號更新", English: "Pickup store number updated"},
+	{CarrierUNIMART, 2103}: {Carrier: CarrierUNIMART, Code: 2103, State: StateException, Chinese: "無取件門市資料", English: "Pickup store not found"},
+	{CarrierFAMI, 3018}:    {Carrier: CarrierFAMI, Code: 3018, State: StateArrivedAtStore, Chinese: "到店尚未取貨,簡訊通知取件", English: "Arrived at store, pickup SMS sent"},
+	{CarrierHILIFE, 3018}:  {Carrier: CarrierHILIFE, Code: 3018, State: StateArrivedAtStore, Chinese: "到店尚未取貨,簡訊通知取件", English: "Arrived at store, pickup SMS sent"},
+	{CarrierOKMART, 3018}:  {Carrier: CarrierOKMART, Code: 3018, State: StateArrivedAtStore, Chinese: "到店尚未取貨,簡訊通知取件", English: "Arrived at store, pickup SMS sent"},
+	{CarrierFAMI, 3019}:    {Carrier: CarrierFAMI, Code: 3019, State: StateReturned, Chinese: "退貨商品已退回寄件門市", English: "Returned to sender store"},
+	{CarrierHILIFE, 3019}:  {Carrier: CarrierHILIFE, Code: 3019, State: StateReturned, Chinese: "退貨商品已退回寄件門市", English: "Returned to sender store"},
+	{CarrierOKMART, 3019}:  {Carrier: CarrierOKMART, Code: 3019, State: StateReturned, Chinese: "退貨商品已退回寄件門市", English: "Returned to sender store"},
+	{CarrierFAMI, 3020}:    {Carrier: CarrierFAMI, Code: 3020, State: StateReturning, Chinese: "貨件未取退回物流中心", English: "Not picked up, returning to distribution center"},
+	{CarrierHILIFE, 3020}:  {Carrier: CarrierHILIFE, Code: 3020, State: StateReturning, Chinese: "貨件未取退回物流中心", English: "Not picked up, returning to distribution center"},
+	{CarrierOKMART, 3020}:  {Carrier: CarrierOKMART, Code: 3020, State: StateReturning, Chinese: "貨件未取退回物流中心", English: "Not picked up, returning to distribution center"},
+	{CarrierFAMI, 3022}:    {Carrier: CarrierFAMI, Code: 3022, State: StatePickedUp, Chinese: "買家已到店取貨", English: "Picked up by buyer"},
+	{CarrierHILIFE, 3022}:  {Carrier: CarrierHILIFE, Code: 3022, State: StatePickedUp, Chinese: "買家已到店取貨", English: "Picked up by buyer"},
+	{CarrierOKMART, 3022}:  {Carrier: CarrierOKMART, Code: 3022, State: StatePickedUp, Chinese: "買家已到店取貨", English: "Picked up by buyer"},
+	{CarrierFAMI, 3023}:    {Carrier: CarrierFAMI, Code: 3023, State: StateReturned, Chinese: "賣家已取買家未取貨", English: "Uncollected parcel collected by seller"},
+	{CarrierHILIFE, 3023}:  {Carrier: CarrierHILIFE, Code: 3023, State: StateReturned, Chinese: "賣家已取買家未取貨", English: "Uncollected parcel collected by seller"},
+	{CarrierOKMART, 3023}:  {Carrier: CarrierOKMART, Code: 3023, State: StateReturned, Chinese: "賣家已取買家未取貨", English: "Uncollected parcel collected by seller"},
+	{CarrierFAMI, 3024}:    {Carrier: CarrierFAMI, Code: 3024, State: StateInTransit, Chinese: "貨件已至物流中心", English: "Arrived at distribution center"},
+	{CarrierHILIFE, 3024}:  {Carrier: CarrierHILIFE, Code: 3024, State: StateInTransit, Chinese: "貨件已至物流中心", English: "Arrived at distribution center"},
+	{CarrierOKMART, 3024}:  {Carrier: CarrierOKMART, Code: 3024, State: StateInTransit, Chinese: "貨件已至物流中心", English: "Arrived at distribution center"},
+	{CarrierFAMI, 3025}:    {Carrier: CarrierFAMI, Code: 3025, State: StateReturning, Chinese: "退貨已退回物流中心", English: "Return arrived at distribution center"},
+	{CarrierHILIFE, 3025}:  {Carrier: CarrierHILIFE, Code: 3025, State: StateReturning, Chinese: "退貨已退回物流中心", English: "Return arrived at distribution center"},
+	{CarrierOKMART, 3025}:  {Carrier: CarrierOKMART, Code: 3025, State: StateReturning, Chinese: "退貨已退回物流中心", English: "Return arrived at distribution center"},
+	{CarrierFAMI, 3032}:    {Carrier: CarrierFAMI, Code: 3032, State: StateInTransit, Chinese: "賣家已到門市寄件", English: "Dropped off at store by seller"},
+	{CarrierHILIFE, 3032}:  {Carrier: CarrierHILIFE, Code: 3032, State: StateInTransit, Chinese: "賣家已到門市寄件", English: "Dropped off at store by seller"},
+	{CarrierOKMART, 3032}:  {Carrier: CarrierOKMART, Code: 3032, State: StateInTransit, Chinese: "賣家已到門市寄件", English: "Dropped off at store by seller"},
+	{CarrierTCAT, 3001}:    {Carrier: CarrierTCAT, Code: 3001, State: StateInTransit, Chinese: "轉運中(即集貨)", English: "In transit (collected)"},
+	{CarrierPOST, 3001}:    {Carrier: CarrierPOST, Code: 3001, State: StateInTransit, Chinese: "轉運中(即集貨)", English: "In transit (collected)"},
+	{CarrierTCAT, 3002}:    {Carrier: CarrierTCAT, Code: 3002, State: StateException, Chinese: "不在家", English: "Recipient not at home"},
+	{CarrierTCAT, 3003}:    {Carrier: CarrierTCAT, Code: 3003, State: StateDelivered, Chinese: "配完", English: "Delivered"},
+	{CarrierPOST, 3003}:    {Carrier: CarrierPOST, Code: 3003, State: StateDelivered, Chinese: "配完", English: "Delivered"},
+	{CarrierTCAT, 3004}:    {Carrier: CarrierTCAT, Code: 3004, State: StateException, Chinese: "送錯營業所", English: "Sent to wrong branch"},
+	{CarrierTCAT, 3005}:    {Carrier: CarrierTCAT, Code: 3005, State: StateException, Chinese: "送錯轉運中心", English: "Sent to wrong hub"},
+	{CarrierTCAT, 3006}:    {Carrier: CarrierTCAT, Code: 3006, State: StateInTransit, Chinese: "配送中", English: "Out for delivery"},
+	{CarrierPOST, 3006}:    {Carrier: CarrierPOST, Code: 3006, State: StateInTransit, Chinese: "配送中", English: "Out for delivery"},
+	{CarrierTCAT, 3007}:    {Carrier: CarrierTCAT, Code: 3007, State: StateException, Chinese: "公司行號休息", English: "Recipient business closed"},
+	{CarrierTCAT, 3008}:    {Carrier: CarrierTCAT, Code: 3008, State: StateException, Chinese: "地址錯誤,查無此人", English: "Wrong address or recipient unknown"},
+	{CarrierTCAT, 3009}:    {Carrier: CarrierTCAT, Code: 3009, State: StateException, Chinese: "搬家", English: "Recipient moved"},
+	{CarrierTCAT, 3010}:    {Carrier: CarrierTCAT, Code: 3010, State: StateInTransit, Chinese: "轉寄", English: "Forwarded to another address"},
+	{CarrierTCAT, 3011}:    {Carrier: CarrierTCAT, Code: 3011, State: StateArrivedAtStore, Chinese: "暫置營業所(收件人要求至營業所取貨)", English: "Held at branch for pickup"},
+	{CarrierTCAT, 3012}:    {Carrier: CarrierTCAT, Code: 3012, State: StateArrivedAtStore, Chinese: "到所(收件人要求到站所取件)", English: "Arrived at branch for pickup"},
+	{CarrierTCAT, 3013}:    {Carrier: CarrierTCAT, Code: 3013, State: StateInTransit, Chinese: "當配下車", English: "Same-day delivery unloaded"},
+	{CarrierTCAT, 3014}:    {Carrier: CarrierTCAT, Code: 3014, State: StateInTransit, Chinese: "當配上車", English: "Same-day delivery loaded"},
+	{CarrierTCAT, 3015}:    {Carrier: CarrierTCAT, Code: 3015, State: StateInTransit, Chinese: "空運配送中", English: "In transit by air"},
+	{CarrierTCAT, 3016}:    {Carrier: CarrierTCAT, Code: 3016, State: StateDelivered, Chinese: "配完(收件人至營業所取貨)", English: "Picked up at branch"},
+	{CarrierTCAT, 3017}:    {Carrier: CarrierTCAT, Code: 3017, State: StateReturning, Chinese: "退回(通知寄件人退回)", English: "Returning to sender"},
+	{CarrierPOST, 3017}:    {Carrier: CarrierPOST, Code: 3017, State: StateReturning, Chinese: "退回(通知寄件人退回)", English: "Returning to sender"},
+}
@@ -0,0 +1,127 @@
+package logistics
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCarrierOf(t *testing.T) {
+	tests := map[string]Carrier{
+		"UNIMARTC2C":    CarrierUNIMART,
+		"UNIMARTFREEZE": CarrierUNIMART,
+		"FAMI":          CarrierFAMI,
+		"hilifec2c":     CarrierHILIFE,
+		"OKMARTC2C":     CarrierOKMART,
+		"TCAT":          CarrierTCAT,
+		"POST":          CarrierPOST,
+		"ECAN":          "ECAN",
+	}
+	for subType, want := range tests {
+		if got := CarrierOf(subType); got != want {
+			t.Errorf("CarrierOf(%q) = %q, want %q", subType, got, want)
+		}
+	}
+}
+
+func TestLookupStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		subType   string
+		code      int
+		wantOK    bool
+		wantState State
+		carrier   Carrier
+	}{
+		{name: "carrier specific", subType: "UNIMARTC2C", code: 2067, wantOK: true, wantState: StatePickedUp, carrier: CarrierUNIMART},
+		{name: "shared store code", subType: "FAMIC2C", code: 3022, wantOK: true, wantState: StatePickedUp, carrier: CarrierFAMI},
+		{name: "shared store code other carrier", subType: "OKMARTC2C", code: 3024, wantOK: true, wantState: StateInTransit, carrier: CarrierOKMART},
+		{name: "home delivery tcat", subType: "TCAT", code: 3003, wantOK: true, wantState: StateDelivered, carrier: CarrierTCAT},
+		{name: "home delivery post", subType: "POST", code: 3003, wantOK: true, wantState: StateDelivered, carrier: CarrierPOST},
+		{name: "post returning", subType: "POST", code: 3017, wantOK: true, wantState: StateReturning, carrier: CarrierPOST},
+		{name: "falls back to shared code", subType: "POST", code: 300, wantOK: true, wantState: StateCreated, carrier: carrierAny},
+		{name: "code of another carrier", subType: "UNIMART", code: 3022, wantState: StateUnknown, carrier: CarrierUNIMART},
+		{name: "tcat only code for post", subType: "POST", code: 3013, wantState: StateUnknown, carrier: CarrierPOST},
+		{name: "unknown code", subType: "TCAT", code: 9999, wantState: StateUnknown, carrier: CarrierTCAT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ok := LookupStatus(tt.subType, tt.code)
+			if ok != tt.wantOK || status.State != tt.wantState || status.Carrier != tt.carrier || status.Code != tt.code {
+				t.Errorf("LookupStatus(%q, %d) = %+v, %v", tt.subType, tt.code, status, ok)
+			}
+			if ok && status.Chinese == "" {
+				t.Errorf("LookupStatus(%q, %d) has no description", tt.subType, tt.code)
+			}
+		})
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	update := &StatusUpdate{LogisticsSubType: "UNIMARTC2C", RtnCode: 2063}
+	if status, ok := update.Status(); !ok || status.State != StateArrivedAtStore {
+		t.Errorf("StatusUpdate.Status() = %+v, %v", status, ok)
+	}
+
+	order := &LogisticsOrder{LogisticsSubType: "TCAT", LogisticsStatus: "3006"}
+	if status, ok := order.Status(); !ok || status.State != StateInTransit {
+		t.Errorf("LogisticsOrder.Status() = %+v, %v", status, ok)
+	}
+
+	order.LogisticsStatus = ""
+	if status, ok := order.Status(); ok || status.State != StateUnknown || status.Carrier != CarrierTCAT {
+		t.Errorf("LogisticsOrder.Status() without status = %+v, %v", status, ok)
+	}
+}
+
+func TestState(t *testing.T) {
+	if StateArrivedAtStore.String() != "ArrivedAtStore" || State(99).String() != "State(99)" {
+		t.Errorf("unexpected names %s, %s", StateArrivedAtStore, State(99))
+	}
+	for _, s := range []State{StatePickedUp, StateDelivered, StateReturned, StateLost, StateFailed} {
+		if !s.IsFinal() {
+			t.Errorf("%s should be final", s)
+		}
+	}
+	for _, s := range []State{StateUnknown, StateCreated, StateInTransit, StateArrivedAtStore, StateException, StateReturning} {
+		if s.IsFinal() {
+			t.Errorf("%s should not be final", s)
+		}
+	}
+}
+
+// TestCatalogueMatchesTable guards against editing status_codes.tsv without running go generate.
+func TestCatalogueMatchesTable(t *testing.T) {
+	file, err := os.Open("status_codes.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		code, _ := strconv.Atoi(fields[1])
+		for _, carrier := range strings.Split(fields[0], ",") {
+			rows++
+			status, ok := statusCatalogue[statusKey{Carrier(carrier), code}]
+			if !ok || status.State.String() != fields[2] || status.Chinese != fields[3] {
+				t.Errorf("%s %d: catalogue has %+v, table has %v; run go generate", carrier, code, status, fields)
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rows != len(statusCatalogue) {
+		t.Errorf("table has %d entries, catalogue has %d; run go generate", rows, len(statusCatalogue))
+	}
+}
@@ -0,0 +1,141 @@
+package logistics
+
+import (
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// DeviceDesktop 電子地圖以電腦版顯示
+	DeviceDesktop = "0"
+
+	// DeviceMobile 電子地圖以行動版顯示
+	DeviceMobile = "1"
+)
+
+// ErrMissingStore 門市選擇回傳中沒有 CVSStoreID
+var ErrMissingStore = errors.New("store selection carries no CVSStoreID")
+
+// StoreMapRequest is the 電子地圖 (Express/map) request. The store picker runs in the
+// buyer's browser, so the request is rendered as a form instead of being sent by the server.
+// The map API takes no CheckMacValue.
+type StoreMapRequest struct {
+	Client *client.ECPayClient `json:"-"`
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// LogisticsType 物流類型, 固定為 CVS
+	LogisticsType string `json:"LogisticsType,omitempty" form:"LogisticsType"`
+
+	// LogisticsSubType 物流子類型 (UNIMART, FAMI, HILIFE, UNIMARTC2C, FAMIC2C, HILIFEC2C, OKMARTC2C...)
+	LogisticsSubType string `json:"LogisticsSubType,omitempty" form:"LogisticsSubType"`
+
+	// IsCollection 是否代收貨款 (Y/N), 決定地圖只列出支援代收的門市
+	IsCollection string `json:"IsCollection,omitempty" form:"IsCollection"`
+
+	// ServerReplyURL 門市選擇完成後, 瀏覽器 POST 回傳門市資料的網址
+	ServerReplyURL string `json:"ServerReplyURL,omitempty" form:"ServerReplyURL"`
+
+	// ExtraData 額外資訊, 原樣回傳
+	ExtraData string `json:"ExtraData,omitempty" form:"ExtraData"`
+
+	// Device 使用設備 (DeviceDesktop / DeviceMobile)
+	Device string `json:"Device,omitempty" form:"Device"`
+}
+
+// StoreMapForm is an Express/map request to be submitted by the buyer's browser
+type StoreMapForm struct {
+	// Action 表單送出網址 (Express/map)
+	Action string `json:"Action"`
+
+	// Values 表單參數
+	Values url.Values `json:"Values"`
+}
+
+// Form returns the map request as a form for browser submission.
+func (r *StoreMapRequest) Form() (*StoreMapForm, error) {
+
+	if r.LogisticsSubType == "" {
+		return nil, fmt.Errorf("電子地圖缺少 LogisticsSubType")
+	}
+	if r.ServerReplyURL == "" {
+		return nil, fmt.Errorf("電子地圖缺少 ServerReplyURL")
+	}
+
+	action, err := r.Client.URL(client.APILogisticsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在複本上補齊預設值, 不修改呼叫端的請求
+	request := *r
+	if request.LogisticsType == "" {
+		request.LogisticsType = LogisticsTypeCVS
+	}
+
+	return &StoreMapForm{
+		Action: action,
+		Values: helpers.ReflectFormValues(&request),
+	}, nil
+}
+
+// HTML renders an HTML page whose form posts the values to ECPay as soon as it loads.
+// Every name and value is HTML-escaped.
+func (f *StoreMapForm) HTML() (string, error) {
+	return helpers.AutoSubmitFormHTML("ecpay-map", "選擇門市", f.Action, f.Values)
+}
+
+// ServeHTTP writes the auto-submitting form page, sending the buyer to the store picker.
+func (f *StoreMapForm) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	helpers.ServeAutoSubmitForm(w, "ecpay-map", "選擇門市", f.Action, f.Values)
+}
+
+// StoreSelection is the store the buyer picked, posted back to ServerReplyURL
+type StoreSelection struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// LogisticsSubType 物流子類型
+	LogisticsSubType string `json:"LogisticsSubType,omitempty" form:"LogisticsSubType"`
+
+	// ConvenienceStore 使用者選擇的門市 (CVSStoreID, CVSStoreName, CVSAddress, CVSOutSide)
+	model.ConvenienceStore `json:",inline"`
+
+	// CVSTelephone 門市電話
+	CVSTelephone string `json:"CVSTelephone,omitempty" form:"CVSTelephone"`
+
+	// ExtraData 額外資訊
+	ExtraData string `json:"ExtraData,omitempty" form:"ExtraData"`
+}
+
+// IsOutlying reports whether the store is on an outlying island (CVSOutSide 1).
+func (s *StoreSelection) IsOutlying() bool {
+	return s.CVSOutSide == "1"
+}
+
+// ParseStoreSelection reads the store selection the buyer's browser posts to ServerReplyURL.
+// ECPay does not sign this post, so treat it as user input: check MerchantTradeNo against
+// the session and re-validate the store when creating the shipment.
+func ParseStoreSelection(r *http.Request) (*StoreSelection, error) {
+
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("error parsing store selection form: %w", err)
+	}
+
+	selection := &StoreSelection{}
+	if err := helpers.BindFormValues(r.PostForm, selection); err != nil {
+		return nil, err
+	}
+	if selection.CVSStoreID == "" {
+		return nil, ErrMissingStore
+	}
+
+	return selection, nil
+}
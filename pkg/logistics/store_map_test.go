@@ -0,0 +1,76 @@
+package logistics
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestStoreMapRequestForm(t *testing.T) {
+	r := &StoreMapRequest{
+		Client:           testClient(&replyDoer{}),
+		Merchant:         model.Merchant{MerchantID: "2000132", MerchantTradeNo: "M0001"},
+		LogisticsSubType: SubTypeFAMIC2C,
+		IsCollection:     "N",
+		ServerReplyURL:   "https://example.com/store",
+	}
+
+	form, err := r.Form()
+	if err != nil {
+		t.Fatalf("Form() error = %v", err)
+	}
+	if !strings.HasSuffix(form.Action, client.APILogisticsMap.Path) {
+		t.Errorf("Action = %s", form.Action)
+	}
+	if form.Values.Get("LogisticsType") != LogisticsTypeCVS || form.Values.Get("LogisticsSubType") != SubTypeFAMIC2C {
+		t.Errorf("Values = %v", form.Values)
+	}
+	if form.Values.Has("CheckMacValue") {
+		t.Error("the map form was signed")
+	}
+	if r.LogisticsType != "" {
+		t.Errorf("Form() changed the request's LogisticsType to %q", r.LogisticsType)
+	}
+
+	for name, modify := range map[string]func(r *StoreMapRequest){
+		"no subtype":   func(r *StoreMapRequest) { r.LogisticsSubType = "" },
+		"no reply url": func(r *StoreMapRequest) { r.ServerReplyURL = "" },
+	} {
+		invalid := *r
+		modify(&invalid)
+		if _, err = invalid.Form(); err == nil {
+			t.Errorf("%s: Form() error = nil", name)
+		}
+	}
+}
+
+func TestParseStoreSelection(t *testing.T) {
+	post := func(values url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/store", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	selection, err := ParseStoreSelection(post(url.Values{
+		"MerchantTradeNo":  {"M0001"},
+		"LogisticsSubType": {SubTypeFAMIC2C},
+		"CVSStoreID":       {"006598"},
+		"CVSStoreName":     {"全家台北店"},
+		"CVSOutSide":       {"1"},
+	}))
+	if err != nil {
+		t.Fatalf("ParseStoreSelection() error = %v", err)
+	}
+	if selection.MerchantTradeNo != "M0001" || selection.CVSStoreID != "006598" || !selection.IsOutlying() {
+		t.Errorf("ParseStoreSelection() = %+v", selection)
+	}
+
+	if _, err = ParseStoreSelection(post(url.Values{"MerchantTradeNo": {"M0001"}})); !errors.Is(err, ErrMissingStore) {
+		t.Errorf("ParseStoreSelection() error = %v, want ErrMissingStore", err)
+	}
+}
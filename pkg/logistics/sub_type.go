@@ -0,0 +1,51 @@
+package logistics
+
+const (
+	// LogisticsTypeCVS 超商取貨
+	LogisticsTypeCVS = "CVS"
+
+	// LogisticsTypeHome 宅配
+	LogisticsTypeHome = "HOME"
+)
+
+const (
+	// SubTypeFAMI 全家 B2C
+	SubTypeFAMI = "FAMI"
+
+	// SubTypeUNIMART 7-ELEVEN B2C
+	SubTypeUNIMART = "UNIMART"
+
+	// SubTypeUNIMARTFreeze 7-ELEVEN B2C 冷凍
+	SubTypeUNIMARTFreeze = "UNIMARTFREEZE"
+
+	// SubTypeHILIFE 萊爾富 B2C
+	SubTypeHILIFE = "HILIFE"
+
+	// SubTypeFAMIC2C 全家店到店
+	SubTypeFAMIC2C = "FAMIC2C"
+
+	// SubTypeUNIMARTC2C 7-ELEVEN 交貨便
+	SubTypeUNIMARTC2C = "UNIMARTC2C"
+
+	// SubTypeHILIFEC2C 萊爾富店到店
+	SubTypeHILIFEC2C = "HILIFEC2C"
+
+	// SubTypeOKMARTC2C OK超商店到店
+	SubTypeOKMARTC2C = "OKMARTC2C"
+
+	// SubTypeTCAT 黑貓宅急便
+	SubTypeTCAT = "TCAT"
+
+	// SubTypePOST 中華郵政
+	SubTypePOST = "POST"
+)
+
+// IsC2C reports whether subType is a 店到店 (C2C) service.
+func IsC2C(subType string) bool {
+	switch subType {
+	case SubTypeFAMIC2C, SubTypeUNIMARTC2C, SubTypeHILIFEC2C, SubTypeOKMARTC2C:
+		return true
+	default:
+		return false
+	}
+}
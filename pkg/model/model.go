@@ -87,6 +87,9 @@ type Receiver struct {
 	// ReceiverEmail 收件人email
 	ReceiverEmail string `json:"ReceiverEmail,omitempty" form:"ReceiverEmail"`
 
+	// ReceiverZipCode 收件人郵遞區號
+	ReceiverZipCode string `json:"ReceiverZipCode,omitempty" form:"ReceiverZipCode"`
+
 	// ReceiverStoreID 收件人門市代號
 	ReceiverStoreID string `json:"ReceiverStoreID,omitempty" form:"ReceiverStoreID"`
 
@@ -99,4 +102,7 @@ type Receiver struct {
 
 type RqHeader struct {
 	Timestamp string `json:"Timestamp"`
+
+	// Revision 串接規格文件版號 (電子發票使用)
+	Revision string `json:"Revision,omitempty"`
 }
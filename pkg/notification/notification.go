@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ReplyOK 綠界要求特店收到通知後回應的內容
+const ReplyOK = "1|OK"
+
+// Parse reads the form posted by ECPay, verifies its CheckMacValue with the
+// client's HashKey/HashIV and binds it into dst. The raw values are returned
+// so that callers can inspect fields dst does not declare. The CheckMacValue must use
+// the algorithm selected by opts (helpers.WithHashAlgorithm), SHA256 by default.
+func Parse(r *http.Request, c *client.ECPayClient, dst any, opts ...helpers.CheckMacOption) (url.Values, error) {
+
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("error parsing notification form: %w", err)
+	}
+
+	if err := validation.ValidateCheckMacValue(r.PostForm, c.HashKey, c.HashIV, opts...); err != nil {
+		return nil, err
+	}
+
+	if err := helpers.BindFormValues(r.PostForm, dst); err != nil {
+		return nil, err
+	}
+
+	return r.PostForm, nil
+}
+
+// Reply writes the body ECPay expects: "1|OK" when err is nil, "0|<message>" otherwise.
+// ECPay keeps retrying a notification until it receives "1|OK".
+func Reply(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err != nil {
+		// 訊息中的 | 與換行會破壞回應格式
+		message := strings.NewReplacer("|", " ", "\r", " ", "\n", " ").Replace(err.Error())
+		_, _ = w.Write([]byte("0|" + message))
+		return
+	}
+	_, _ = w.Write([]byte(ReplyOK))
+}
+
+// Serve parses and verifies the notification into dst, runs callback and replies to ECPay.
+// It is the common body of the SDK's notification handlers; opts are passed to Parse.
+func Serve(w http.ResponseWriter, r *http.Request, c *client.ECPayClient, dst any, callback func() error, opts ...helpers.CheckMacOption) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := Parse(r, c, dst, opts...); err != nil {
+		c.Log().Warn("Rejected ECPay notification", "path", r.URL.Path, "error", err)
+		Reply(w, err)
+		return
+	}
+
+	if callback != nil {
+		if err := callback(); err != nil {
+			c.Log().Error("ECPay notification callback failed", "path", r.URL.Path, "error", err)
+			Reply(w, err)
+			return
+		}
+	}
+
+	Reply(w, nil)
+}
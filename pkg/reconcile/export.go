@@ -0,0 +1,52 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader 差異報表 CSV 欄位
+var csvHeader = []string{
+	"Kind", "MerchantID", "MerchantTradeNo", "TradeNos",
+	"LocalAmount", "ECPayAmount", "LocalRefunded", "ECPayRefunded", "Detail",
+}
+
+// WriteCSV writes the discrepancies as CSV with a header row. Multiple trade numbers
+// are joined with "|".
+func (r *Report) WriteCSV(w io.Writer) error {
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, d := range r.Discrepancies {
+		record := []string{
+			string(d.Kind),
+			d.MerchantID,
+			d.MerchantTradeNo,
+			strings.Join(d.TradeNos, "|"),
+			strconv.Itoa(d.LocalAmount),
+			strconv.Itoa(d.ECPayAmount),
+			strconv.Itoa(d.LocalRefunded),
+			strconv.Itoa(d.ECPayRefunded),
+			d.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the whole report, summary included, as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,343 @@
+package reconcile
+
+import (
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/trade"
+	"sort"
+)
+
+// Order is a local order record to be reconciled against ECPay
+type Order interface {
+	// Merchant 訂單的特店識別資訊 (MerchantID + MerchantTradeNo)
+	Merchant() model.Merchant
+
+	// Amount 訂單金額
+	Amount() int
+
+	// Paid 本地是否已標記為已付款
+	Paid() bool
+
+	// RefundedAmount 本地已記錄的退款金額
+	RefundedAmount() int
+}
+
+// Record is one ECPay payment record of an order
+type Record struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo"`
+
+	// Amount 交易金額
+	Amount int `json:"Amount"`
+
+	// Paid 綠界是否已收到款項
+	Paid bool `json:"Paid"`
+
+	// RefundedAmount 綠界已退款金額
+	RefundedAmount int `json:"RefundedAmount"`
+
+	// PaymentDate 付款時間
+	PaymentDate string `json:"PaymentDate"`
+}
+
+// FromTradeInfo converts a QueryTradeInfo result into a record. QueryTradeInfo carries no
+// refunds, so for credit card trades pass the detail from ECPayTrade.QueryCreditDetail to fill
+// RefundedAmount from its close data; detail may be nil for other payment types.
+func FromTradeInfo(info *trade.TradeInfo, detail *trade.CreditDetail) Record {
+
+	record := Record{
+		Merchant:    model.Merchant{MerchantID: info.MerchantID, MerchantTradeNo: info.MerchantTradeNo},
+		TradeNo:     info.TradeNo,
+		Amount:      info.TradeAmt,
+		Paid:        info.IsPaid(),
+		PaymentDate: info.PaymentDate,
+	}
+
+	if detail != nil {
+		for _, closed := range detail.CloseData {
+			if isRefundStatus(closed.Status) {
+				record.RefundedAmount += abs(closed.Amount)
+			}
+		}
+	}
+
+	return record
+}
+
+// FromReportRows converts the rows of a daily report into records. Rows of the same
+// trade are merged; negative amounts are treated as refunds of that trade. A trade is
+// paid when one of its non-refund rows is not explicitly unpaid (未付款, 訂單失敗), so a status
+// the SDK does not know, such as 退貨中, still surfaces the payment. Reports without a
+// payment status column (the credit card settlement detail) only list settled trades.
+func FromReportRows(merchantID string, rows []trade.ReportRow) []Record {
+
+	records := make([]Record, 0, len(rows))
+	index := map[string]int{}
+	for _, row := range rows {
+		key := row.MerchantTradeNo + "/" + row.TradeNo
+		i, ok := index[key]
+		if !ok {
+			i = len(records)
+			index[key] = i
+			records = append(records, Record{
+				Merchant:    model.Merchant{MerchantID: merchantID, MerchantTradeNo: row.MerchantTradeNo},
+				TradeNo:     row.TradeNo,
+				PaymentDate: row.PaymentDate,
+			})
+		}
+		if row.Amount < 0 {
+			records[i].RefundedAmount += -row.Amount
+			continue
+		}
+		records[i].Amount += row.Amount
+		if !isUnpaidStatus(row.PaymentStatus) {
+			records[i].Paid = true
+		}
+	}
+
+	return records
+}
+
+// isUnpaidStatus reports whether a report's payment status column says no money was
+// received (PaymentStatus 0: 未付款, 2: 訂單失敗).
+func isUnpaidStatus(status string) bool {
+	switch status {
+	case "0", "未付款", "2", "訂單失敗":
+		return true
+	}
+	return false
+}
+
+// isRefundStatus reports whether a credit card close record is a refund (退刷).
+func isRefundStatus(status string) bool {
+	switch status {
+	case "退刷", "已退刷":
+		return true
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Kind 差異類型
+type Kind string
+
+const (
+	// PaidButUnknown 綠界已收款, 本地沒有此訂單
+	PaidButUnknown Kind = "paid_but_unknown"
+
+	// KnownButUnpaid 本地標記已付款, 綠界沒有收款紀錄
+	KnownButUnpaid Kind = "known_but_unpaid"
+
+	// PaymentNotRecorded 綠界已收款, 本地仍為未付款
+	PaymentNotRecorded Kind = "payment_not_recorded"
+
+	// AmountMismatch 綠界收款金額與本地訂單金額不同
+	AmountMismatch Kind = "amount_mismatch"
+
+	// DuplicatePayment 同一筆訂單在綠界有多筆收款
+	DuplicatePayment Kind = "duplicate_payment"
+
+	// RefundNotRecorded 綠界已退款, 本地未記錄 (或記錄金額較少)
+	RefundNotRecorded Kind = "refund_not_recorded"
+)
+
+// Discrepancy is one difference between the local orders and ECPay
+type Discrepancy struct {
+	Kind Kind `json:"Kind"`
+
+	// Merchant 訂單的特店識別資訊
+	model.Merchant `json:",inline"`
+
+	// TradeNos 相關的綠界交易編號
+	TradeNos []string `json:"TradeNos,omitempty"`
+
+	// LocalAmount 本地訂單金額
+	LocalAmount int `json:"LocalAmount"`
+
+	// ECPayAmount 綠界收款金額合計
+	ECPayAmount int `json:"ECPayAmount"`
+
+	// LocalRefunded 本地退款金額
+	LocalRefunded int `json:"LocalRefunded"`
+
+	// ECPayRefunded 綠界退款金額合計
+	ECPayRefunded int `json:"ECPayRefunded"`
+
+	// Detail 差異說明
+	Detail string `json:"Detail"`
+}
+
+// Report is the result of a reconciliation
+type Report struct {
+	// LocalOrders 本地訂單數
+	LocalOrders int `json:"LocalOrders"`
+
+	// ECPayRecords 綠界紀錄數
+	ECPayRecords int `json:"ECPayRecords"`
+
+	// Matched 無差異的訂單數
+	Matched int `json:"Matched"`
+
+	// Discrepancies 差異明細, 依 MerchantTradeNo 排序
+	Discrepancies []Discrepancy `json:"Discrepancies"`
+}
+
+// ByKind returns the discrepancies of kind k.
+func (r *Report) ByKind(k Kind) []Discrepancy {
+	var result []Discrepancy
+	for _, d := range r.Discrepancies {
+		if d.Kind == k {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func key(m model.Merchant) string {
+	return m.MerchantID + "/" + m.MerchantTradeNo
+}
+
+// Reconcile compares local orders against ECPay records, matching them by
+// MerchantID + MerchantTradeNo.
+func Reconcile(orders []Order, records []Record) *Report {
+
+	report := &Report{LocalOrders: len(orders), ECPayRecords: len(records)}
+
+	grouped := map[string][]Record{}
+	for _, record := range records {
+		k := key(record.Merchant)
+		grouped[k] = append(grouped[k], record)
+	}
+
+	seen := map[string]bool{}
+	for _, order := range orders {
+		merchant := order.Merchant()
+		k := key(merchant)
+		seen[k] = true
+
+		found := compare(order, merchant, grouped[k])
+		if len(found) == 0 {
+			report.Matched++
+		}
+		report.Discrepancies = append(report.Discrepancies, found...)
+	}
+
+	for k, group := range grouped {
+		if seen[k] {
+			continue
+		}
+		if len(paidRecords(group)) > 0 {
+			d := summarize(PaidButUnknown, group[0].Merchant, group)
+			d.Detail = "綠界已收款, 本地沒有此訂單"
+			report.Discrepancies = append(report.Discrepancies, d)
+			continue
+		}
+		if d := summarize(RefundNotRecorded, group[0].Merchant, group); d.ECPayRefunded > 0 {
+			d.Detail = fmt.Sprintf("綠界退款 %d 元, 本地沒有此訂單也沒有收款紀錄", d.ECPayRefunded)
+			report.Discrepancies = append(report.Discrepancies, d)
+		}
+	}
+
+	sort.SliceStable(report.Discrepancies, func(i, j int) bool {
+		a, b := report.Discrepancies[i], report.Discrepancies[j]
+		if a.MerchantTradeNo != b.MerchantTradeNo {
+			return a.MerchantTradeNo < b.MerchantTradeNo
+		}
+		return a.Kind < b.Kind
+	})
+
+	return report
+}
+
+func paidRecords(records []Record) []Record {
+	var paid []Record
+	for _, record := range records {
+		if record.Paid {
+			paid = append(paid, record)
+		}
+	}
+	return paid
+}
+
+// summarize totals the paid amount and every refund of records, including refunds of
+// trades without a paid record.
+func summarize(kind Kind, merchant model.Merchant, records []Record) Discrepancy {
+	d := Discrepancy{Kind: kind, Merchant: merchant}
+	for _, record := range records {
+		if !record.Paid && record.RefundedAmount == 0 {
+			continue
+		}
+		d.TradeNos = append(d.TradeNos, record.TradeNo)
+		if record.Paid {
+			d.ECPayAmount += record.Amount
+		}
+		d.ECPayRefunded += record.RefundedAmount
+	}
+	return d
+}
+
+// compare returns the discrepancies between one local order and its ECPay records.
+func compare(order Order, merchant model.Merchant, records []Record) []Discrepancy {
+
+	paid := paidRecords(records)
+
+	newDiscrepancy := func(kind Kind, detail string) Discrepancy {
+		d := summarize(kind, merchant, records)
+		d.LocalAmount = order.Amount()
+		d.LocalRefunded = order.RefundedAmount()
+		d.Detail = detail
+		return d
+	}
+
+	var found []Discrepancy
+	if len(paid) == 0 {
+		if order.Paid() {
+			found = append(found, newDiscrepancy(KnownButUnpaid, "本地標記已付款, 綠界沒有收款紀錄"))
+		}
+	} else {
+		found = append(found, comparePaid(order, paid, newDiscrepancy)...)
+	}
+
+	// 沒有收款紀錄的退款也要回報
+	if r := newDiscrepancy(RefundNotRecorded, ""); r.ECPayRefunded > r.LocalRefunded {
+		r.Detail = fmt.Sprintf("綠界退款 %d 元, 本地記錄 %d 元", r.ECPayRefunded, r.LocalRefunded)
+		found = append(found, r)
+	}
+
+	return found
+}
+
+// comparePaid returns the discrepancies of an order that ECPay has paid records for.
+func comparePaid(order Order, paid []Record, newDiscrepancy func(kind Kind, detail string) Discrepancy) []Discrepancy {
+
+	var found []Discrepancy
+	if !order.Paid() {
+		found = append(found, newDiscrepancy(PaymentNotRecorded, "綠界已收款, 本地仍為未付款"))
+	}
+
+	tradeNos := map[string]bool{}
+	for _, record := range paid {
+		tradeNos[record.TradeNo] = true
+	}
+	if len(tradeNos) > 1 {
+		found = append(found, newDiscrepancy(DuplicatePayment, fmt.Sprintf("綠界有 %d 筆收款", len(tradeNos))))
+	}
+
+	d := newDiscrepancy(AmountMismatch, "")
+	if len(tradeNos) == 1 && d.ECPayAmount != d.LocalAmount {
+		d.Detail = fmt.Sprintf("綠界收款 %d 元, 本地訂單 %d 元", d.ECPayAmount, d.LocalAmount)
+		found = append(found, d)
+	}
+
+	return found
+}
@@ -0,0 +1,194 @@
+package reconcile
+
+import (
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/trade"
+	"testing"
+)
+
+type order struct {
+	tradeNo  string
+	amount   int
+	paid     bool
+	refunded int
+}
+
+func (o order) Merchant() model.Merchant {
+	return model.Merchant{MerchantID: "3002607", MerchantTradeNo: o.tradeNo}
+}
+func (o order) Amount() int         { return o.amount }
+func (o order) Paid() bool          { return o.paid }
+func (o order) RefundedAmount() int { return o.refunded }
+
+func record(merchantTradeNo, tradeNo string, amount int, paid bool, refunded int) Record {
+	return Record{
+		Merchant:       model.Merchant{MerchantID: "3002607", MerchantTradeNo: merchantTradeNo},
+		TradeNo:        tradeNo,
+		Amount:         amount,
+		Paid:           paid,
+		RefundedAmount: refunded,
+	}
+}
+
+func TestReconcileKinds(t *testing.T) {
+	tests := []struct {
+		name         string
+		orders       []Order
+		records      []Record
+		want         []Kind
+		wantMatched  int
+		wantRefunded int
+	}{
+		{
+			name:        "matched",
+			orders:      []Order{order{tradeNo: "A1", amount: 100, paid: true}},
+			records:     []Record{record("A1", "T1", 100, true, 0)},
+			wantMatched: 1,
+		},
+		{
+			name:        "unpaid on both sides",
+			orders:      []Order{order{tradeNo: "A1", amount: 100}},
+			records:     []Record{record("A1", "T1", 100, false, 0)},
+			wantMatched: 1,
+		},
+		{
+			name:    "paid but unknown",
+			records: []Record{record("A1", "T1", 100, true, 0)},
+			want:    []Kind{PaidButUnknown},
+		},
+		{
+			name:   "known but unpaid",
+			orders: []Order{order{tradeNo: "A1", amount: 100, paid: true}},
+			want:   []Kind{KnownButUnpaid},
+		},
+		{
+			name:    "payment not recorded",
+			orders:  []Order{order{tradeNo: "A1", amount: 100}},
+			records: []Record{record("A1", "T1", 100, true, 0)},
+			want:    []Kind{PaymentNotRecorded},
+		},
+		{
+			name:    "amount mismatch",
+			orders:  []Order{order{tradeNo: "A1", amount: 100, paid: true}},
+			records: []Record{record("A1", "T1", 90, true, 0)},
+			want:    []Kind{AmountMismatch},
+		},
+		{
+			name:    "duplicate payment",
+			orders:  []Order{order{tradeNo: "A1", amount: 100, paid: true}},
+			records: []Record{record("A1", "T1", 100, true, 0), record("A1", "T2", 100, true, 0)},
+			want:    []Kind{DuplicatePayment},
+		},
+		{
+			name:         "refund not recorded",
+			orders:       []Order{order{tradeNo: "A1", amount: 100, paid: true, refunded: 10}},
+			records:      []Record{record("A1", "T1", 100, true, 40)},
+			want:         []Kind{RefundNotRecorded},
+			wantRefunded: 40,
+		},
+		{
+			name:        "refund recorded",
+			orders:      []Order{order{tradeNo: "A1", amount: 100, paid: true, refunded: 40}},
+			records:     []Record{record("A1", "T1", 100, true, 40)},
+			wantMatched: 1,
+		},
+		{
+			name:         "refund without paid record",
+			orders:       []Order{order{tradeNo: "A1", amount: 100, paid: true}},
+			records:      []Record{record("A1", "T1", 0, false, 100)},
+			want:         []Kind{KnownButUnpaid, RefundNotRecorded},
+			wantRefunded: 100,
+		},
+		{
+			name:         "refund of an unknown order",
+			records:      []Record{record("A1", "T1", 0, false, 100)},
+			want:         []Kind{RefundNotRecorded},
+			wantRefunded: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Reconcile(tt.orders, tt.records)
+
+			if report.Matched != tt.wantMatched {
+				t.Errorf("Matched = %d, want %d", report.Matched, tt.wantMatched)
+			}
+			if len(report.Discrepancies) != len(tt.want) {
+				t.Fatalf("Discrepancies = %+v, want kinds %v", report.Discrepancies, tt.want)
+			}
+			for i, kind := range tt.want {
+				d := report.Discrepancies[i]
+				if d.Kind != kind || d.MerchantTradeNo != "A1" || d.Detail == "" {
+					t.Errorf("Discrepancies[%d] = %+v, want kind %s", i, d, kind)
+				}
+				if kind == RefundNotRecorded && d.ECPayRefunded != tt.wantRefunded {
+					t.Errorf("ECPayRefunded = %d, want %d", d.ECPayRefunded, tt.wantRefunded)
+				}
+			}
+		})
+	}
+}
+
+func TestFromReportRows(t *testing.T) {
+	rows := []trade.ReportRow{
+		{MerchantTradeNo: "A1", TradeNo: "T1", PaymentStatus: "已付款", Amount: 100},
+		{MerchantTradeNo: "A2", TradeNo: "T2", PaymentStatus: "未付款", Amount: 200},
+		{MerchantTradeNo: "A3", TradeNo: "T3", PaymentStatus: "訂單失敗", Amount: 300},
+		{MerchantTradeNo: "A4", TradeNo: "T4", PaymentStatus: "退貨中", Amount: 400},
+		{MerchantTradeNo: "A5", TradeNo: "T5", Amount: 500},
+		{MerchantTradeNo: "A1", TradeNo: "T1", PaymentStatus: "已付款", Amount: -30},
+	}
+
+	records := FromReportRows("3002607", rows)
+	if len(records) != 5 {
+		t.Fatalf("FromReportRows() returned %d records, want 5 (the refund row merges into A1)", len(records))
+	}
+
+	want := map[string]struct {
+		paid     bool
+		amount   int
+		refunded int
+	}{
+		"A1": {paid: true, amount: 100, refunded: 30},
+		"A2": {paid: false, amount: 200},
+		"A3": {paid: false, amount: 300},
+		"A4": {paid: true, amount: 400},
+		"A5": {paid: true, amount: 500},
+	}
+	for _, r := range records {
+		w := want[r.MerchantTradeNo]
+		if r.MerchantID != "3002607" || r.Paid != w.paid || r.Amount != w.amount || r.RefundedAmount != w.refunded {
+			t.Errorf("record %s = %+v, want %+v", r.MerchantTradeNo, r, w)
+		}
+	}
+
+	// 狀態不在已知清單 (退貨中) 的交易不可消失
+	report := Reconcile(nil, records)
+	if report.ECPayRecords != 5 {
+		t.Errorf("ECPayRecords = %d, want 5", report.ECPayRecords)
+	}
+	unknown := report.ByKind(PaidButUnknown)
+	if len(unknown) != 3 || unknown[0].MerchantTradeNo != "A1" || unknown[1].MerchantTradeNo != "A4" || unknown[2].MerchantTradeNo != "A5" {
+		t.Errorf("PaidButUnknown = %+v, want A1, A4 and A5", unknown)
+	}
+}
+
+func TestFromTradeInfo(t *testing.T) {
+	info := &trade.TradeInfo{Merchant: model.Merchant{MerchantTradeNo: "A1"}, TradeNo: "T1", TradeAmt: 100, TradeStatus: trade.TradeStatusPaid}
+	detail := &trade.CreditDetail{CloseData: []trade.CreditCloseRecord{
+		{Status: "已關帳", Amount: 100},
+		{Status: "退刷", Amount: -30},
+		{Status: "已退刷", Amount: 20},
+		{Status: "退刷中", Amount: 50},
+	}}
+
+	record := FromTradeInfo(info, detail)
+	if !record.Paid || record.Amount != 100 || record.RefundedAmount != 50 {
+		t.Errorf("FromTradeInfo() = %+v, want paid 100 with 50 refunded", record)
+	}
+
+	if record := FromTradeInfo(info, nil); record.RefundedAmount != 0 {
+		t.Errorf("FromTradeInfo(nil detail) refunded %d", record.RefundedAmount)
+	}
+}
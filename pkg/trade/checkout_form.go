@@ -0,0 +1,48 @@
+package trade
+
+import (
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"net/http"
+	"net/url"
+)
+
+// CheckoutForm is a signed AioCheckOut request to be submitted by the buyer's browser,
+// which is how ECPay's cashier page is meant to be reached.
+type CheckoutForm struct {
+	// Action 表單送出網址 (AioCheckOut)
+	Action string `json:"Action"`
+
+	// Values 已含 CheckMacValue 的表單參數
+	Values url.Values `json:"Values"`
+}
+
+// CreateCheckoutForm signs the trade and returns it as a form for browser submission.
+func (e *ECPayTrade) CreateCheckoutForm() (*CheckoutForm, error) {
+
+	formData, err := e.signedFormValues()
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := e.Client.URL(client.APIAioCheckOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckoutForm{
+		Action: action,
+		Values: formData,
+	}, nil
+}
+
+// HTML renders an HTML page whose form posts the values to ECPay as soon as it loads.
+// Every name and value is HTML-escaped.
+func (f *CheckoutForm) HTML() (string, error) {
+	return helpers.AutoSubmitFormHTML("ecpay-checkout", "前往付款", f.Action, f.Values)
+}
+
+// ServeHTTP writes the auto-submitting form page, redirecting the buyer to ECPay.
+func (f *CheckoutForm) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	helpers.ServeAutoSubmitForm(w, "ecpay-checkout", "前往付款", f.Action, f.Values)
+}
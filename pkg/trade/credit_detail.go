@@ -0,0 +1,237 @@
+package trade
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"strconv"
+)
+
+// CreditDetailQuery queries the card-side detail of one credit card authorisation
+type CreditDetailQuery struct {
+
+	// BaseModel 通用參數
+	model.BaseModel `json:",inline"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID,omitempty" form:"MerchantID"`
+
+	// CreditRefundID 信用卡授權交易單號 (gwsr)
+	CreditRefundID int64 `json:"CreditRefundId,omitempty" form:"CreditRefundId"`
+
+	// CreditAmount 授權金額
+	CreditAmount int `json:"CreditAmount,omitempty" form:"CreditAmount"`
+
+	// CreditCheckCode 商家檢查碼 (特店管理後台 > 系統開發管理 > 系統介接設定)
+	CreditCheckCode string `json:"CreditCheckCode,omitempty" form:"CreditCheckCode"`
+}
+
+// CreditDetail is the card-side history of an authorisation
+type CreditDetail struct {
+
+	// TradeID 信用卡授權交易單號
+	TradeID int64 `json:"TradeID"`
+
+	// Amount 授權金額
+	Amount int `json:"amount"`
+
+	// ClosedAmount 已關帳金額
+	ClosedAmount int `json:"clsamt"`
+
+	// AuthTime 授權時間
+	AuthTime string `json:"authtime"`
+
+	// Status 交易狀態 (已授權, 要關帳, 已關帳, 已取消, 操作取消...)
+	Status string `json:"status"`
+
+	// CloseData 關帳 / 退刷紀錄
+	CloseData []CreditCloseRecord `json:"close_data"`
+
+	// AuthCode 授權碼, 由 ECPayTrade.QueryCreditDetail 自 QueryTradeInfo 補上
+	AuthCode string `json:"auth_code,omitempty"`
+
+	// Card4No 卡號末4碼, 由 ECPayTrade.QueryCreditDetail 自 QueryTradeInfo 補上
+	Card4No string `json:"card4no,omitempty"`
+
+	// Card6No 卡號前6碼, 由 ECPayTrade.QueryCreditDetail 自 QueryTradeInfo 補上
+	Card6No string `json:"card6no,omitempty"`
+}
+
+// CreditCloseRecord is one close or refund of an authorisation
+type CreditCloseRecord struct {
+
+	// Status 狀態 (已關帳, 已退刷...)
+	Status string `json:"status"`
+
+	// SNo 關帳序號
+	SNo string `json:"sno"`
+
+	// Amount 金額
+	Amount int `json:"amount"`
+
+	// DateTime 處理時間
+	DateTime string `json:"datetime"`
+}
+
+// ECPay 在此 API 中的數值欄位有時以字串回傳, 以 number 別名統一解析
+type number int64
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = number(v)
+	return nil
+}
+
+// 序號類欄位可能以數字或字串回傳, 以 text 別名保留原始內容 (含前導 0)
+type text string
+
+func (t *text) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*t = text(s)
+		return nil
+	}
+	*t = text(data)
+	return nil
+}
+
+func (d *CreditDetail) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TradeID   number              `json:"TradeID"`
+		Amount    number              `json:"amount"`
+		Clsamt    number              `json:"clsamt"`
+		AuthTime  string              `json:"authtime"`
+		Status    string              `json:"status"`
+		CloseData []CreditCloseRecord `json:"close_data"`
+		AuthCode  string              `json:"auth_code"`
+		Card4No   string              `json:"card4no"`
+		Card6No   string              `json:"card6no"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*d = CreditDetail{
+		TradeID:      int64(raw.TradeID),
+		Amount:       int(raw.Amount),
+		ClosedAmount: int(raw.Clsamt),
+		AuthTime:     raw.AuthTime,
+		Status:       raw.Status,
+		CloseData:    raw.CloseData,
+		AuthCode:     raw.AuthCode,
+		Card4No:      raw.Card4No,
+		Card6No:      raw.Card6No,
+	}
+	return nil
+}
+
+func (r *CreditCloseRecord) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Status   string `json:"status"`
+		SNo      text   `json:"sno"`
+		Amount   number `json:"amount"`
+		DateTime string `json:"datetime"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = CreditCloseRecord{
+		Status:   raw.Status,
+		SNo:      string(raw.SNo),
+		Amount:   int(raw.Amount),
+		DateTime: raw.DateTime,
+	}
+	return nil
+}
+
+// Query reads the credit card detail of the authorisation.
+func (q *CreditDetailQuery) Query() (*CreditDetail, error) {
+	return q.QueryContext(context.Background())
+}
+
+// QueryContext is like Query but carries ctx to the outgoing request.
+func (q *CreditDetailQuery) QueryContext(ctx context.Context) (*CreditDetail, error) {
+
+	if q.CreditRefundID == 0 || q.CreditAmount <= 0 || q.CreditCheckCode == "" {
+		return nil, errors.New("查詢信用卡明細需設定 CreditRefundID, CreditAmount 與 CreditCheckCode")
+	}
+
+	body, err := sendSigned(ctx, q.Client, client.APICreditQueryTrade, q)
+	if err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		RtnMsg   string        `json:"RtnMsg"`
+		RtnValue *CreditDetail `json:"RtnValue"`
+	}{}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error decoding credit detail: %w", err)
+	}
+	if response.RtnValue == nil {
+		return nil, fmt.Errorf("查詢信用卡明細失敗 失敗原因 : %s", response.RtnMsg)
+	}
+
+	return response.RtnValue, nil
+}
+
+// QueryCreditDetail returns the full card-side history of this order. It looks up the
+// authorisation with QueryTradeInfo, then queries its credit detail with the merchant's
+// CreditCheckCode, filling in the authorisation code and card fragments.
+func (e *ECPayTrade) QueryCreditDetail(creditCheckCode string) (*CreditDetail, error) {
+	return e.QueryCreditDetailContext(context.Background(), creditCheckCode)
+}
+
+// QueryCreditDetailContext is like QueryCreditDetail but carries ctx to the outgoing requests.
+func (e *ECPayTrade) QueryCreditDetailContext(ctx context.Context, creditCheckCode string) (*CreditDetail, error) {
+
+	info, err := e.QueryTradeInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.Gwsr == 0 {
+		return nil, fmt.Errorf("訂單 %s 沒有信用卡授權紀錄", e.MerchantTradeNo)
+	}
+
+	amount := info.Amount
+	if amount == 0 {
+		amount = info.TradeAmt
+	}
+
+	query := &CreditDetailQuery{
+		BaseModel:       model.BaseModel{Client: e.Client},
+		MerchantID:      e.MerchantID,
+		CreditRefundID:  info.Gwsr,
+		CreditAmount:    amount,
+		CreditCheckCode: creditCheckCode,
+	}
+
+	detail, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detail.AuthCode = info.AuthCode
+	detail.Card4No = info.Card4No
+	detail.Card6No = info.Card6No
+
+	return detail, nil
+}
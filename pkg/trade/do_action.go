@@ -0,0 +1,130 @@
+package trade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"net/url"
+)
+
+// CreditAction 信用卡請退款執行動作
+type CreditAction string
+
+const (
+	// ActionClose 關帳 (請款)
+	ActionClose CreditAction = "C"
+
+	// ActionRefund 退刷, 可部分退款
+	ActionRefund CreditAction = "R"
+
+	// ActionCancel 取消關帳
+	ActionCancel CreditAction = "E"
+
+	// ActionAbandon 放棄 (未關帳的授權)
+	ActionAbandon CreditAction = "N"
+)
+
+// DoActionRequest closes, refunds, cancels or abandons a credit card authorisation
+type DoActionRequest struct {
+
+	// BaseModel 通用參數
+	model.BaseModel `json:",inline"`
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo,omitempty" form:"TradeNo"`
+
+	// Action 執行動作 (C: 關帳, R: 退刷, E: 取消, N: 放棄)
+	Action CreditAction `json:"Action,omitempty" form:"Action"`
+
+	// TotalAmount 金額, 所有動作皆必填且不可超過授權金額; 放棄時為原授權金額
+	TotalAmount int `json:"TotalAmount,omitempty" form:"TotalAmount"`
+
+	// AuthorisedAmount 原授權金額, 設定時檢查金額不超過此金額, 放棄時須相同 (不送出)
+	AuthorisedAmount int `json:"-"`
+}
+
+// DoActionResult is ECPay's reply to DoAction
+type DoActionResult struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo,omitempty" form:"TradeNo"`
+
+	// RtnCode 執行結果 (1: 成功)
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 執行訊息
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+}
+
+// validate rejects requests ECPay would refuse before they are signed.
+func (r *DoActionRequest) validate() error {
+
+	if r.MerchantTradeNo == "" || r.TradeNo == "" {
+		return errors.New("信用卡請退款需設定 MerchantTradeNo 與 TradeNo")
+	}
+
+	switch r.Action {
+	case ActionClose, ActionRefund, ActionCancel, ActionAbandon:
+	default:
+		return fmt.Errorf("不支援的信用卡請退款動作 %q", r.Action)
+	}
+
+	// 綠界要求每個動作都帶 TotalAmount
+	if r.TotalAmount <= 0 {
+		return fmt.Errorf("動作 %s 的金額必須大於 0", r.Action)
+	}
+	if r.AuthorisedAmount > 0 {
+		if r.TotalAmount > r.AuthorisedAmount {
+			return fmt.Errorf("動作 %s 的金額 %d 超過授權金額 %d", r.Action, r.TotalAmount, r.AuthorisedAmount)
+		}
+		if r.Action == ActionAbandon && r.TotalAmount != r.AuthorisedAmount {
+			return fmt.Errorf("放棄授權的金額 %d 須與授權金額 %d 相同", r.TotalAmount, r.AuthorisedAmount)
+		}
+	}
+
+	return nil
+}
+
+// DoAction executes the credit card action. A result whose RtnCode is not 1 is
+// returned together with an error carrying ECPay's RtnMsg.
+func (r *DoActionRequest) DoAction() (*DoActionResult, error) {
+	return r.DoActionContext(context.Background())
+}
+
+// DoActionContext is like DoAction but carries ctx to the outgoing request.
+func (r *DoActionRequest) DoActionContext(ctx context.Context) (*DoActionResult, error) {
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := sendSigned(ctx, r.Client, client.APICreditDoAction, r)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	result := &DoActionResult{}
+	if err = helpers.BindFormValues(values, result); err != nil {
+		return nil, err
+	}
+
+	if result.RtnCode != 1 {
+		return result, fmt.Errorf("信用卡請退款失敗 失敗原因 : %s", result.RtnMsg)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package trade
+
+import (
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// formDoer records the form posted to ECPay and answers with reply.
+type formDoer struct {
+	reply string
+	sent  url.Values
+}
+
+func (d *formDoer) Do(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	d.sent = req.PostForm
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(d.reply)),
+	}, nil
+}
+
+func clientWith(doer client.Doer) *client.ECPayClient {
+	c := testClient()
+	c.HTTPClient = doer
+	return c
+}
+
+func TestDoActionSendsTotalAmount(t *testing.T) {
+	for _, action := range []CreditAction{ActionClose, ActionRefund, ActionCancel, ActionAbandon} {
+		t.Run(string(action), func(t *testing.T) {
+			doer := &formDoer{reply: "MerchantID=3002607&MerchantTradeNo=A1&TradeNo=2303121530280427&RtnCode=1&RtnMsg=成功"}
+			r := &DoActionRequest{
+				BaseModel:        model.BaseModel{Client: clientWith(doer)},
+				Merchant:         model.Merchant{MerchantTradeNo: "A1"},
+				TradeNo:          "2303121530280427",
+				Action:           action,
+				TotalAmount:      100,
+				AuthorisedAmount: 100,
+			}
+
+			result, err := r.DoAction()
+			if err != nil {
+				t.Fatalf("DoAction() error = %v", err)
+			}
+			if result.RtnCode != 1 {
+				t.Errorf("RtnCode = %d", result.RtnCode)
+			}
+			if doer.sent.Get("Action") != string(action) || doer.sent.Get("TotalAmount") != "100" {
+				t.Errorf("sent Action = %q, TotalAmount = %q", doer.sent.Get("Action"), doer.sent.Get("TotalAmount"))
+			}
+			if doer.sent.Get("CheckMacValue") == "" {
+				t.Error("request was not signed")
+			}
+		})
+	}
+}
+
+func TestDoActionValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     CreditAction
+		amount     int
+		authorised int
+		wantErr    bool
+	}{
+		{name: "close", action: ActionClose, amount: 100},
+		{name: "close without amount", action: ActionClose, wantErr: true},
+		{name: "close over authorised", action: ActionClose, amount: 101, authorised: 100, wantErr: true},
+		{name: "partial refund", action: ActionRefund, amount: 40, authorised: 100},
+		{name: "refund without amount", action: ActionRefund, wantErr: true},
+		{name: "refund over authorised", action: ActionRefund, amount: 101, authorised: 100, wantErr: true},
+		{name: "cancel", action: ActionCancel, amount: 100, authorised: 100},
+		{name: "cancel without amount", action: ActionCancel, wantErr: true},
+		{name: "cancel over authorised", action: ActionCancel, amount: 101, authorised: 100, wantErr: true},
+		{name: "abandon", action: ActionAbandon, amount: 100, authorised: 100},
+		{name: "abandon without amount", action: ActionAbandon, wantErr: true},
+		{name: "abandon part of authorised", action: ActionAbandon, amount: 40, authorised: 100, wantErr: true},
+		{name: "unknown action", action: "X", amount: 100, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &DoActionRequest{
+				Merchant:         model.Merchant{MerchantTradeNo: "A1"},
+				TradeNo:          "2303121530280427",
+				Action:           tt.action,
+				TotalAmount:      tt.amount,
+				AuthorisedAmount: tt.authorised,
+			}
+			if err := r.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (&DoActionRequest{Action: ActionClose, TotalAmount: 100}).validate(); err == nil {
+		t.Error("validate() accepted a request without MerchantTradeNo and TradeNo")
+	}
+}
+
+func TestDoActionRtnCodeError(t *testing.T) {
+	doer := &formDoer{reply: "RtnCode=10200047&RtnMsg=Cant not find the trade data."}
+	r := &DoActionRequest{
+		BaseModel:   model.BaseModel{Client: clientWith(doer)},
+		Merchant:    model.Merchant{MerchantTradeNo: "A1"},
+		TradeNo:     "2303121530280427",
+		Action:      ActionRefund,
+		TotalAmount: 100,
+	}
+
+	result, err := r.DoAction()
+	if err == nil || result == nil || result.RtnCode != 10200047 {
+		t.Fatalf("DoAction() = %+v, %v; want the result and an error", result, err)
+	}
+	if !strings.Contains(err.Error(), "Cant not find the trade data.") {
+		t.Errorf("error %q does not carry RtnMsg", err)
+	}
+}
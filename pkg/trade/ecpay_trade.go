@@ -1,8 +1,12 @@
 package trade
 
 import (
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"net/url"
 )
 
 // ECPayTrade is a struct containing information for an ECPay trade
@@ -29,7 +33,7 @@ type ECPayTrade struct {
 	// ChoosePayment 選擇預設付款方式
 	ChoosePayment string `json:"ChoosePayment,omitempty" form:"ChoosePayment"`
 
-	// EncryptType CheckMacValue加密類型, 使用SHA256加密
+	// EncryptType CheckMacValue加密類型, 全方位金流固定為 1 (SHA256), 未設定時自動帶入
 	EncryptType int `json:"EncryptType,omitempty" form:"EncryptType"`
 
 	// StoreID 特店旗下店舖代號
@@ -67,23 +71,129 @@ type ECPayTrade struct {
 
 	// Language 語系設定 (ENG: 英語, KOR: 韓語, JPN: 日語, CHI: 簡體中文)
 	Language string `json:"Language,omitempty" form:"Language"`
+
+	// Payment 付款方式設定, 簽章前會寫入 ChoosePayment 及對應的付款參數
+	Payment PaymentMethod `json:"-"`
+
+	// PaymentInfoURL Server端回傳付款相關資訊 (ATM / CVS / BARCODE 取號結果)
+	PaymentInfoURL string `json:"PaymentInfoURL,omitempty" form:"PaymentInfoURL"`
+
+	// ClientRedirectURL Client端回傳付款相關資訊 (ATM / CVS / BARCODE 取號結果)
+	ClientRedirectURL string `json:"ClientRedirectURL,omitempty" form:"ClientRedirectURL"`
+
+	// ExpireDate ATM 允許繳費有效天數 (1 ~ 60 天, 預設 3 天)
+	ExpireDate int `json:"ExpireDate,omitempty" form:"ExpireDate"`
+
+	// StoreExpireDate 超商繳費截止時間 (CVS 以分鐘為單位, BARCODE 以天為單位)
+	StoreExpireDate int `json:"StoreExpireDate,omitempty" form:"StoreExpireDate"`
+
+	// Desc1 超商交易描述1 (會出現在超商繳費平台螢幕上)
+	Desc1 string `json:"Desc_1,omitempty" form:"Desc_1"`
+
+	// Desc2 超商交易描述2
+	Desc2 string `json:"Desc_2,omitempty" form:"Desc_2"`
+
+	// Desc3 超商交易描述3
+	Desc3 string `json:"Desc_3,omitempty" form:"Desc_3"`
+
+	// Desc4 超商交易描述4
+	Desc4 string `json:"Desc_4,omitempty" form:"Desc_4"`
+
+	// CreditInstallment 信用卡刷卡分期期數 (以逗號分隔, 如 3,6,12,18,24,30N)
+	CreditInstallment string `json:"CreditInstallment,omitempty" form:"CreditInstallment"`
+
+	// Redeem 信用卡是否使用紅利折抵 (Y: 使用)
+	Redeem string `json:"Redeem,omitempty" form:"Redeem"`
+
+	// UnionPay 銀聯卡交易選項 (0: 可選銀聯, 1: 只使用銀聯, 2: 不可使用銀聯)
+	UnionPay int `json:"UnionPay,omitempty" form:"UnionPay"`
+
+	// BindingCard 記憶卡號 (1: 使用)
+	BindingCard int `json:"BindingCard,omitempty" form:"BindingCard"`
+
+	// MerchantMemberID 記憶卡號識別碼 (特店代號 + 廠商會員編號)
+	MerchantMemberID string `json:"MerchantMemberID,omitempty" form:"MerchantMemberID"`
+
+	// PeriodAmount 定期定額每次授權金額, 須與 TotalAmount 相同
+	PeriodAmount int `json:"PeriodAmount,omitempty" form:"PeriodAmount"`
+
+	// PeriodType 定期定額週期種類 (D: 天, M: 月, Y: 年)
+	PeriodType string `json:"PeriodType,omitempty" form:"PeriodType"`
+
+	// Frequency 定期定額執行頻率
+	Frequency int `json:"Frequency,omitempty" form:"Frequency"`
+
+	// ExecTimes 定期定額執行次數
+	ExecTimes int `json:"ExecTimes,omitempty" form:"ExecTimes"`
+
+	// PeriodReturnURL 定期定額每次授權結果通知網址
+	PeriodReturnURL string `json:"PeriodReturnURL,omitempty" form:"PeriodReturnURL"`
 }
 
 // CreateAioPayment sends an HTTP POST request to create a payment transaction with AioPayment method.
 // It takes an ECPayClient as a parameter and returns the response body as a string and an error, if any.
 // If an error occurs during the request, it will be returned.
 func (e *ECPayTrade) CreateAioPayment() (string, error) {
+	return e.CreateAioPaymentContext(context.Background())
+}
 
-	formData := helpers.ReflectFormValues(e)
-
-	checkMacValue := helpers.GenerateCheckMacValue(formData, e.Client.HashKey, e.Client.HashIV)
+// CreateAioPaymentContext is like CreateAioPayment but carries ctx to the outgoing request.
+func (e *ECPayTrade) CreateAioPaymentContext(ctx context.Context) (string, error) {
 
-	formData.Set("CheckMacValue", checkMacValue)
+	formData, err := e.signedFormValues()
+	if err != nil {
+		return "", err
+	}
 
-	body, err := helpers.SendFormData(e.Client, formData)
+	body, err := helpers.SendFormDataContext(ctx, e.Client, client.APIAioCheckOut, formData)
 	if err != nil {
 		return "", err
 	}
 
 	return string(body), nil
 }
+
+// signedFormValues converts the trade into AioCheckOut form values and appends its CheckMacValue.
+func (e *ECPayTrade) signedFormValues() (url.Values, error) {
+
+	if e.Payment != nil {
+		if err := e.Payment.Apply(e); err != nil {
+			return nil, err
+		}
+	}
+
+	// AioCheckOut V5 只接受 SHA256 (EncryptType=1), 未設定時補上
+	switch e.EncryptType {
+	case 0:
+		e.EncryptType = int(helpers.HashSHA256)
+	case int(helpers.HashSHA256):
+	default:
+		return nil, fmt.Errorf("AioCheckOut 僅支援 EncryptType=1 (SHA256), 收到 %d", e.EncryptType)
+	}
+
+	formData := helpers.ReflectFormValues(e)
+	e.Client.DefaultMerchantID(formData)
+
+	checkMacValue := helpers.GenerateCheckMacValue(formData, e.Client.HashKey, e.Client.HashIV,
+		helpers.WithHashAlgorithm(helpers.HashSHA256),
+		helpers.WithLogger(e.Client.SigningLog()))
+
+	formData.Set("CheckMacValue", checkMacValue)
+
+	return formData, nil
+}
+
+// sendSigned converts request into form values, signs them with SHA256 and posts them to api.
+// It is shared by the query and action APIs of the payment service.
+func sendSigned(ctx context.Context, c *client.ECPayClient, api client.API, request any) ([]byte, error) {
+
+	formData := helpers.ReflectFormValues(request)
+	c.DefaultMerchantID(formData)
+
+	checkMacValue := helpers.GenerateCheckMacValue(formData, c.HashKey, c.HashIV,
+		helpers.WithLogger(c.SigningLog()))
+
+	formData.Set("CheckMacValue", checkMacValue)
+
+	return helpers.SendFormDataContext(ctx, c, api, formData)
+}
@@ -0,0 +1,96 @@
+package trade
+
+import (
+	"context"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/notification"
+	"net/http"
+)
+
+// PaymentResult is the payment result ECPay posts to ReturnURL
+type PaymentResult struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// StoreID 特店旗下店舖代號
+	StoreID string `json:"StoreID,omitempty" form:"StoreID"`
+
+	// RtnCode 交易狀態 (1: 付款成功, 其餘為失敗)
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 交易訊息
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo,omitempty" form:"TradeNo"`
+
+	// TradeAmt 交易金額
+	TradeAmt int `json:"TradeAmt,omitempty" form:"TradeAmt"`
+
+	// PaymentDate 付款時間 (yyyy/MM/dd HH:mm:ss)
+	PaymentDate string `json:"PaymentDate,omitempty" form:"PaymentDate"`
+
+	// PaymentType 特店選擇的付款方式
+	PaymentType string `json:"PaymentType,omitempty" form:"PaymentType"`
+
+	// PaymentTypeChargeFee 交易手續費金額
+	PaymentTypeChargeFee int `json:"PaymentTypeChargeFee,omitempty" form:"PaymentTypeChargeFee"`
+
+	// PlatformID 特約合作平台商代號
+	PlatformID string `json:"PlatformID,omitempty" form:"PlatformID"`
+
+	// TradeDate 訂單成立時間 (yyyy/MM/dd HH:mm:ss)
+	TradeDate string `json:"TradeDate,omitempty" form:"TradeDate"`
+
+	// SimulatePaid 是否為模擬付款 (1: 模擬付款, 請勿出貨)
+	SimulatePaid int `json:"SimulatePaid,omitempty" form:"SimulatePaid"`
+
+	// CustomField1 自訂名稱欄位1
+	CustomField1 string `json:"CustomField1,omitempty" form:"CustomField1"`
+
+	// CustomField2 自訂名稱欄位2
+	CustomField2 string `json:"CustomField2,omitempty" form:"CustomField2"`
+
+	// CustomField3 自訂名稱欄位3
+	CustomField3 string `json:"CustomField3,omitempty" form:"CustomField3"`
+
+	// CustomField4 自訂名稱欄位4
+	CustomField4 string `json:"CustomField4,omitempty" form:"CustomField4"`
+
+	// CheckMacValue 檢查碼
+	CheckMacValue string `json:"CheckMacValue,omitempty" form:"CheckMacValue"`
+}
+
+// IsPaid reports whether ECPay reported a successful payment.
+func (p *PaymentResult) IsPaid() bool {
+	return p.RtnCode == 1
+}
+
+// IsSimulated reports whether the notification was triggered by the vendor portal's
+// 模擬付款 button. Such orders have not actually been paid and must not be shipped.
+func (p *PaymentResult) IsSimulated() bool {
+	return p.SimulatePaid == 1
+}
+
+// NotificationHandler receives the payment result ECPay posts to ReturnURL.
+// It verifies the CheckMacValue, passes the typed result to OnPayment and
+// answers "1|OK", or "0|<error>" when verification or the callback fails.
+type NotificationHandler struct {
+	// Client 提供驗證 CheckMacValue 的 HashKey / HashIV
+	Client *client.ECPayClient
+
+	// OnPayment 收到已驗證的付款結果時呼叫, 回傳錯誤時綠界會重新通知
+	OnPayment func(ctx context.Context, result *PaymentResult) error
+}
+
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := &PaymentResult{}
+	notification.Serve(w, r, h.Client, result, func() error {
+		if h.OnPayment == nil {
+			return nil
+		}
+		return h.OnPayment(r.Context(), result)
+	})
+}
@@ -0,0 +1,90 @@
+package trade
+
+import (
+	"context"
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func testClient() *client.ECPayClient {
+	return client.StagePayment.Client(client.Stage)
+}
+
+func paymentForm(c *client.ECPayClient, algorithm helpers.HashAlgorithm) url.Values {
+	values := url.Values{
+		"MerchantID":      {"3002607"},
+		"MerchantTradeNo": {"ecpay20230312153023"},
+		"RtnCode":         {"1"},
+		"RtnMsg":          {"交易成功"},
+		"TradeNo":         {"2303121530280427"},
+		"TradeAmt":        {"30000"},
+		"SimulatePaid":    {"0"},
+	}
+	values.Set("CheckMacValue", helpers.GenerateCheckMacValue(values, c.HashKey, c.HashIV, helpers.WithHashAlgorithm(algorithm)))
+	return values
+}
+
+func postForm(handler http.Handler, method string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/ecpay/return", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNotificationHandler(t *testing.T) {
+	c := testClient()
+
+	tamperedForm := paymentForm(c, helpers.HashSHA256)
+	tamperedForm.Set("TradeAmt", "1")
+
+	tests := []struct {
+		name       string
+		method     string
+		form       url.Values
+		callback   error
+		wantStatus int
+		wantBody   string
+		wantCalled bool
+	}{
+		{name: "verified", method: http.MethodPost, form: paymentForm(c, helpers.HashSHA256), wantStatus: http.StatusOK, wantBody: "1|OK", wantCalled: true},
+		{name: "tampered", method: http.MethodPost, form: tamperedForm, wantStatus: http.StatusOK, wantBody: "0|CheckMacValue mismatch"},
+		{name: "md5 downgrade", method: http.MethodPost, form: paymentForm(c, helpers.HashMD5), wantStatus: http.StatusOK, wantBody: "0|CheckMacValue mismatch"},
+		{name: "missing mac", method: http.MethodPost, form: url.Values{"RtnCode": {"1"}}, wantStatus: http.StatusOK, wantBody: "0|CheckMacValue is missing from the response"},
+		{
+			name: "callback error", method: http.MethodPost, form: paymentForm(c, helpers.HashSHA256), callback: errors.New("db down|retry"),
+			wantStatus: http.StatusOK, wantBody: "0|db down retry", wantCalled: true,
+		},
+		{name: "get", method: http.MethodGet, form: url.Values{}, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *PaymentResult
+			handler := &NotificationHandler{Client: c, OnPayment: func(_ context.Context, result *PaymentResult) error {
+				got = result
+				return tt.callback
+			}}
+
+			rec := postForm(handler, tt.method, tt.form)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("OnPayment called = %v, want %v", got != nil, tt.wantCalled)
+			}
+			if got != nil && (!got.IsPaid() || got.TradeAmt != 30000 || got.MerchantTradeNo != "ecpay20230312153023" || got.IsSimulated()) {
+				t.Errorf("unexpected result %+v", got)
+			}
+		})
+	}
+}
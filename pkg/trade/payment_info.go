@@ -0,0 +1,144 @@
+package trade
+
+import (
+	"context"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/notification"
+	"net/http"
+	"time"
+)
+
+// 取號成功的 RtnCode
+const (
+	// RtnCodeATMIssued ATM 取號成功
+	RtnCodeATMIssued = 2
+
+	// RtnCodeCVSIssued 超商代碼 / 條碼取號成功
+	RtnCodeCVSIssued = 10100073
+)
+
+// PaymentInfo is the payment instruction of an offline payment (ATM virtual account,
+// CVS payment code or barcode), posted to PaymentInfoURL and returned by QueryPaymentInfo
+type PaymentInfo struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// StoreID 特店旗下店舖代號
+	StoreID string `json:"StoreID,omitempty" form:"StoreID"`
+
+	// RtnCode 取號結果 (ATM: 2, CVS / BARCODE: 10100073 為成功)
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 交易訊息
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo,omitempty" form:"TradeNo"`
+
+	// TradeAmt 交易金額
+	TradeAmt int `json:"TradeAmt,omitempty" form:"TradeAmt"`
+
+	// PaymentType 付款方式
+	PaymentType string `json:"PaymentType,omitempty" form:"PaymentType"`
+
+	// TradeDate 訂單成立時間
+	TradeDate string `json:"TradeDate,omitempty" form:"TradeDate"`
+
+	// BankCode ATM 繳費銀行代碼
+	BankCode string `json:"BankCode,omitempty" form:"BankCode"`
+
+	// VAccount ATM 繳費虛擬帳號
+	VAccount string `json:"vAccount,omitempty" form:"vAccount"`
+
+	// ExpireDate 繳費期限 (yyyy/MM/dd 或 yyyy/MM/dd HH:mm:ss)
+	ExpireDate string `json:"ExpireDate,omitempty" form:"ExpireDate"`
+
+	// PaymentNo 超商繳費代碼
+	PaymentNo string `json:"PaymentNo,omitempty" form:"PaymentNo"`
+
+	// Barcode1 條碼第一段號碼
+	Barcode1 string `json:"Barcode1,omitempty" form:"Barcode1"`
+
+	// Barcode2 條碼第二段號碼
+	Barcode2 string `json:"Barcode2,omitempty" form:"Barcode2"`
+
+	// Barcode3 條碼第三段號碼
+	Barcode3 string `json:"Barcode3,omitempty" form:"Barcode3"`
+
+	// CustomField1 自訂名稱欄位1
+	CustomField1 string `json:"CustomField1,omitempty" form:"CustomField1"`
+
+	// CustomField2 自訂名稱欄位2
+	CustomField2 string `json:"CustomField2,omitempty" form:"CustomField2"`
+
+	// CustomField3 自訂名稱欄位3
+	CustomField3 string `json:"CustomField3,omitempty" form:"CustomField3"`
+
+	// CustomField4 自訂名稱欄位4
+	CustomField4 string `json:"CustomField4,omitempty" form:"CustomField4"`
+
+	// CheckMacValue 檢查碼
+	CheckMacValue string `json:"CheckMacValue,omitempty" form:"CheckMacValue"`
+}
+
+// IsIssued reports whether ECPay issued the payment instruction successfully.
+func (p *PaymentInfo) IsIssued() bool {
+	return p.RtnCode == RtnCodeATMIssued || p.RtnCode == RtnCodeCVSIssued
+}
+
+// PaymentInfoHandler receives the payment instruction ECPay posts to PaymentInfoURL
+// after an ATM, CVS or BARCODE order has been issued its account or code.
+type PaymentInfoHandler struct {
+	// Client 提供驗證 CheckMacValue 的 HashKey / HashIV
+	Client *client.ECPayClient
+
+	// OnPaymentInfo 收到已驗證的取號結果時呼叫, 回傳錯誤時綠界會重新通知
+	OnPaymentInfo func(ctx context.Context, info *PaymentInfo) error
+}
+
+func (h *PaymentInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info := &PaymentInfo{}
+	notification.Serve(w, r, h.Client, info, func() error {
+		if h.OnPaymentInfo == nil {
+			return nil
+		}
+		return h.OnPaymentInfo(r.Context(), info)
+	})
+}
+
+// QueryPaymentInfo reads the ATM / CVS / BARCODE payment instruction of this order.
+// The response CheckMacValue is verified before it is returned.
+func (e *ECPayTrade) QueryPaymentInfo() (*PaymentInfo, error) {
+	return e.QueryPaymentInfoContext(context.Background())
+}
+
+// QueryPaymentInfoContext is like QueryPaymentInfo but carries ctx to the outgoing request.
+func (e *ECPayTrade) QueryPaymentInfoContext(ctx context.Context) (*PaymentInfo, error) {
+
+	query := &tradeInfoQuery{
+		MerchantID:      e.MerchantID,
+		MerchantTradeNo: e.MerchantTradeNo,
+		TimeStamp:       time.Now().Unix(),
+		PlatformID:      e.PlatformID,
+	}
+
+	body, err := sendSigned(ctx, e.Client, client.APIQueryPaymentInfo, query)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseSignedResponse(e.Client, body)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PaymentInfo{}
+	if err = helpers.BindFormValues(values, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
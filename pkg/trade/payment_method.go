@@ -0,0 +1,266 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChoosePayment 付款方式
+const (
+	ChooseAll      = "ALL"
+	ChooseCredit   = "Credit"
+	ChooseWebATM   = "WebATM"
+	ChooseATM      = "ATM"
+	ChooseCVS      = "CVS"
+	ChooseBarcode  = "BARCODE"
+	ChooseApplePay = "ApplePay"
+	ChooseTWQR     = "TWQR"
+	ChooseBNPL     = "BNPL"
+)
+
+// ErrInvalidPaymentOption 付款方式設定不合法, 請求不會送出
+var ErrInvalidPaymentOption = errors.New("invalid payment option")
+
+// PaymentMethod is a typed ChoosePayment setting. Apply validates it against the
+// trade and writes ChoosePayment, ChooseSubPayment and the method-specific fields.
+type PaymentMethod interface {
+	Apply(e *ECPayTrade) error
+}
+
+func invalidPayment(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidPaymentOption, fmt.Sprintf(format, args...))
+}
+
+// choose sets ChoosePayment, rejecting a conflicting value set by hand.
+func (e *ECPayTrade) choose(payment string) error {
+	if e.ChoosePayment != "" && e.ChoosePayment != payment {
+		return invalidPayment("ChoosePayment 為 %s, 與付款方式設定 %s 不符", e.ChoosePayment, payment)
+	}
+	e.ChoosePayment = payment
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Installment 信用卡分期期數
+type Installment string
+
+const (
+	Installment3  Installment = "3"
+	Installment6  Installment = "6"
+	Installment12 Installment = "12"
+	Installment18 Installment = "18"
+	Installment24 Installment = "24"
+
+	// Installment30N 圓夢彈性分期
+	Installment30N Installment = "30N"
+)
+
+// UnionPay 銀聯卡交易選項
+const (
+	UnionPaySelectable = 0
+	UnionPayOnly       = 1
+	UnionPayDisabled   = 2
+)
+
+// CreditPayment 信用卡 (一次付清 / 分期 / 紅利 / 記憶卡號)
+type CreditPayment struct {
+	// Installments 提供的分期期數, 不可與紅利折抵同時使用
+	Installments []Installment
+
+	// Redeem 使用紅利折抵
+	Redeem bool
+
+	// UnionPay 銀聯卡交易選項
+	UnionPay int
+
+	// BindingCard 記憶卡號, 需同時設定 MerchantMemberID
+	BindingCard bool
+
+	// MerchantMemberID 記憶卡號識別碼
+	MerchantMemberID string
+}
+
+func (p CreditPayment) Apply(e *ECPayTrade) error {
+
+	if len(p.Installments) > 0 && p.Redeem {
+		return invalidPayment("信用卡分期不可與紅利折抵同時使用")
+	}
+	if p.UnionPay < UnionPaySelectable || p.UnionPay > UnionPayDisabled {
+		return invalidPayment("UnionPay 必須為 0, 1 或 2")
+	}
+	if p.UnionPay == UnionPayOnly && (len(p.Installments) > 0 || p.Redeem) {
+		return invalidPayment("銀聯卡不支援分期與紅利折抵")
+	}
+	if p.BindingCard && p.MerchantMemberID == "" {
+		return invalidPayment("記憶卡號需設定 MerchantMemberID")
+	}
+
+	installments := make([]string, 0, len(p.Installments))
+	for _, i := range p.Installments {
+		if !oneOf(string(i), "3", "6", "12", "18", "24", "30N") {
+			return invalidPayment("不支援的分期期數 %s", i)
+		}
+		installments = append(installments, string(i))
+	}
+
+	if err := e.choose(ChooseCredit); err != nil {
+		return err
+	}
+	e.CreditInstallment = strings.Join(installments, ",")
+	if p.Redeem {
+		e.Redeem = "Y"
+	}
+	e.UnionPay = p.UnionPay
+	if p.BindingCard {
+		e.BindingCard = 1
+		e.MerchantMemberID = p.MerchantMemberID
+	}
+	return nil
+}
+
+// ATMPayment ATM 櫃員機
+type ATMPayment struct {
+	// Bank 指定銀行 (ChooseSubPayment, 如 TAISHIN, ESUN, BOT, FUBON, CHINATRUST, FIRST, LAND, CATHAY, TACHONG, PANHSIN), 空白由消費者選擇
+	Bank string
+
+	// ExpireDays 允許繳費有效天數 (1 ~ 60 天), 0 使用綠界預設 3 天
+	ExpireDays int
+
+	// PaymentInfoURL Server端回傳取號結果網址
+	PaymentInfoURL string
+
+	// ClientRedirectURL Client端回傳取號結果網址
+	ClientRedirectURL string
+}
+
+func (p ATMPayment) Apply(e *ECPayTrade) error {
+
+	if p.ExpireDays < 0 || p.ExpireDays > 60 {
+		return invalidPayment("ATM 繳費有效天數必須介於 1 ~ 60 天")
+	}
+
+	if err := e.choose(ChooseATM); err != nil {
+		return err
+	}
+	e.ChooseSubPayment = p.Bank
+	e.ExpireDate = p.ExpireDays
+	e.PaymentInfoURL = p.PaymentInfoURL
+	e.ClientRedirectURL = p.ClientRedirectURL
+	return nil
+}
+
+// CVSPayment 超商代碼
+type CVSPayment struct {
+	// Store 指定超商 (ChooseSubPayment: CVS, OK, FAMILY, HILIFE, IBON), 空白由消費者選擇
+	Store string
+
+	// ExpireMinutes 繳費截止時間 (1 ~ 43200 分鐘), 0 使用綠界預設 10080 分鐘 (7 天)
+	ExpireMinutes int
+
+	// Desc 超商繳費平台螢幕上顯示的交易描述 (Desc_1 ~ Desc_4)
+	Desc [4]string
+
+	// PaymentInfoURL Server端回傳取號結果網址
+	PaymentInfoURL string
+
+	// ClientRedirectURL Client端回傳取號結果網址
+	ClientRedirectURL string
+}
+
+func (p CVSPayment) Apply(e *ECPayTrade) error {
+
+	if p.Store != "" && !oneOf(p.Store, "CVS", "OK", "FAMILY", "HILIFE", "IBON") {
+		return invalidPayment("不支援的超商 %s", p.Store)
+	}
+	if p.ExpireMinutes < 0 || p.ExpireMinutes > 43200 {
+		return invalidPayment("超商代碼繳費截止時間必須介於 1 ~ 43200 分鐘")
+	}
+
+	if err := e.choose(ChooseCVS); err != nil {
+		return err
+	}
+	e.ChooseSubPayment = p.Store
+	e.StoreExpireDate = p.ExpireMinutes
+	e.Desc1, e.Desc2, e.Desc3, e.Desc4 = p.Desc[0], p.Desc[1], p.Desc[2], p.Desc[3]
+	e.PaymentInfoURL = p.PaymentInfoURL
+	e.ClientRedirectURL = p.ClientRedirectURL
+	return nil
+}
+
+// BarcodePayment 超商條碼
+type BarcodePayment struct {
+	// ExpireDays 繳費截止天數 (1 ~ 30 天), 0 使用綠界預設 7 天
+	ExpireDays int
+
+	// PaymentInfoURL Server端回傳取號結果網址
+	PaymentInfoURL string
+
+	// ClientRedirectURL Client端回傳取號結果網址
+	ClientRedirectURL string
+}
+
+func (p BarcodePayment) Apply(e *ECPayTrade) error {
+
+	if p.ExpireDays < 0 || p.ExpireDays > 30 {
+		return invalidPayment("超商條碼繳費截止天數必須介於 1 ~ 30 天")
+	}
+
+	if err := e.choose(ChooseBarcode); err != nil {
+		return err
+	}
+	e.ChooseSubPayment = "BARCODE"
+	e.StoreExpireDate = p.ExpireDays
+	e.PaymentInfoURL = p.PaymentInfoURL
+	e.ClientRedirectURL = p.ClientRedirectURL
+	return nil
+}
+
+// WebATMPayment 網路 ATM
+type WebATMPayment struct {
+	// Bank 指定銀行 (ChooseSubPayment), 空白由消費者選擇
+	Bank string
+}
+
+func (p WebATMPayment) Apply(e *ECPayTrade) error {
+	if err := e.choose(ChooseWebATM); err != nil {
+		return err
+	}
+	e.ChooseSubPayment = p.Bank
+	return nil
+}
+
+// ApplePayPayment Apple Pay
+type ApplePayPayment struct{}
+
+func (ApplePayPayment) Apply(e *ECPayTrade) error {
+	return e.choose(ChooseApplePay)
+}
+
+// TWQRPayment 台灣Pay
+type TWQRPayment struct{}
+
+func (TWQRPayment) Apply(e *ECPayTrade) error {
+	return e.choose(ChooseTWQR)
+}
+
+// BNPLMinAmount 無卡分期最低交易金額
+const BNPLMinAmount = 3000
+
+// BNPLPayment 無卡分期 (先買後付)
+type BNPLPayment struct{}
+
+func (BNPLPayment) Apply(e *ECPayTrade) error {
+	if e.TotalAmount < BNPLMinAmount {
+		return invalidPayment("無卡分期交易金額需大於等於 %d 元", BNPLMinAmount)
+	}
+	return e.choose(ChooseBNPL)
+}
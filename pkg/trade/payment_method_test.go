@@ -0,0 +1,132 @@
+package trade
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"testing"
+)
+
+func TestPaymentMethodApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		trade   ECPayTrade
+		payment PaymentMethod
+		wantErr bool
+		check   func(t *testing.T, e *ECPayTrade)
+	}{
+		{
+			name:    "credit installments",
+			payment: CreditPayment{Installments: []Installment{Installment3, Installment30N}},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseCredit || e.CreditInstallment != "3,30N" {
+					t.Errorf("ChoosePayment = %q, CreditInstallment = %q", e.ChoosePayment, e.CreditInstallment)
+				}
+			},
+		},
+		{
+			name:    "credit redeem and binding card",
+			payment: CreditPayment{Redeem: true, BindingCard: true, MerchantMemberID: "member1"},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.Redeem != "Y" || e.BindingCard != 1 || e.MerchantMemberID != "member1" {
+					t.Errorf("Redeem = %q, BindingCard = %d, MerchantMemberID = %q", e.Redeem, e.BindingCard, e.MerchantMemberID)
+				}
+			},
+		},
+		{name: "installments with redeem", payment: CreditPayment{Installments: []Installment{Installment6}, Redeem: true}, wantErr: true},
+		{name: "unsupported installment", payment: CreditPayment{Installments: []Installment{"5"}}, wantErr: true},
+		{name: "unionpay out of range", payment: CreditPayment{UnionPay: 3}, wantErr: true},
+		{name: "unionpay with installments", payment: CreditPayment{UnionPay: UnionPayOnly, Installments: []Installment{Installment3}}, wantErr: true},
+		{name: "binding card without member", payment: CreditPayment{BindingCard: true}, wantErr: true},
+		{name: "conflicting ChoosePayment", trade: ECPayTrade{ChoosePayment: ChooseATM}, payment: CreditPayment{}, wantErr: true},
+		{
+			name:    "atm",
+			payment: ATMPayment{Bank: "ESUN", ExpireDays: 60},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseATM || e.ChooseSubPayment != "ESUN" || e.ExpireDate != 60 {
+					t.Errorf("ChoosePayment = %q, ChooseSubPayment = %q, ExpireDate = %d", e.ChoosePayment, e.ChooseSubPayment, e.ExpireDate)
+				}
+			},
+		},
+		{name: "atm expire too long", payment: ATMPayment{ExpireDays: 61}, wantErr: true},
+		{
+			name:    "cvs",
+			payment: CVSPayment{Store: "FAMILY", ExpireMinutes: 43200, Desc: [4]string{"a", "", "", "d"}},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseCVS || e.ChooseSubPayment != "FAMILY" || e.StoreExpireDate != 43200 || e.Desc1 != "a" || e.Desc4 != "d" {
+					t.Errorf("unexpected CVS fields %+v", e)
+				}
+			},
+		},
+		{name: "cvs unknown store", payment: CVSPayment{Store: "7-11"}, wantErr: true},
+		{name: "cvs expire too long", payment: CVSPayment{ExpireMinutes: 43201}, wantErr: true},
+		{
+			name:    "barcode",
+			payment: BarcodePayment{ExpireDays: 30},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseBarcode || e.ChooseSubPayment != "BARCODE" || e.StoreExpireDate != 30 {
+					t.Errorf("ChoosePayment = %q, ChooseSubPayment = %q, StoreExpireDate = %d", e.ChoosePayment, e.ChooseSubPayment, e.StoreExpireDate)
+				}
+			},
+		},
+		{name: "barcode expire too long", payment: BarcodePayment{ExpireDays: 31}, wantErr: true},
+		{name: "bnpl below minimum", trade: ECPayTrade{TotalAmount: BNPLMinAmount - 1}, payment: BNPLPayment{}, wantErr: true},
+		{
+			name:    "bnpl",
+			trade:   ECPayTrade{TotalAmount: BNPLMinAmount},
+			payment: BNPLPayment{},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseBNPL {
+					t.Errorf("ChoosePayment = %q", e.ChoosePayment)
+				}
+			},
+		},
+		{
+			name:    "same ChoosePayment set by hand",
+			trade:   ECPayTrade{ChoosePayment: ChooseTWQR},
+			payment: TWQRPayment{},
+			check: func(t *testing.T, e *ECPayTrade) {
+				if e.ChoosePayment != ChooseTWQR {
+					t.Errorf("ChoosePayment = %q", e.ChoosePayment)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.trade
+			err := tt.payment.Apply(&e)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPaymentOption) {
+					t.Fatalf("Apply() error = %v, want ErrInvalidPaymentOption", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			tt.check(t, &e)
+		})
+	}
+}
+
+func TestSignedFormValuesAppliesPayment(t *testing.T) {
+	e := &ECPayTrade{
+		BaseModel:   model.BaseModel{Client: testClient()},
+		TotalAmount: 100,
+		Payment:     ATMPayment{Bank: "BOT", ExpireDays: 7},
+	}
+
+	values, err := e.signedFormValues()
+	if err != nil {
+		t.Fatalf("signedFormValues() error = %v", err)
+	}
+	if values.Get("ChoosePayment") != ChooseATM || values.Get("ChooseSubPayment") != "BOT" || values.Get("ExpireDate") != "7" {
+		t.Errorf("payment fields not sent: %v", values)
+	}
+
+	e = &ECPayTrade{BaseModel: model.BaseModel{Client: testClient()}, Payment: CVSPayment{Store: "7-11"}}
+	if _, err := e.signedFormValues(); !errors.Is(err, ErrInvalidPaymentOption) {
+		t.Errorf("signedFormValues() error = %v, want ErrInvalidPaymentOption", err)
+	}
+}
@@ -0,0 +1,268 @@
+package trade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/notification"
+	"net/http"
+	"time"
+)
+
+// PeriodType 定期定額週期種類
+const (
+	PeriodDay   = "D"
+	PeriodMonth = "M"
+	PeriodYear  = "Y"
+)
+
+// PeriodicPayment 信用卡定期定額
+type PeriodicPayment struct {
+	// Amount 每次授權金額, 0 則使用 TotalAmount
+	Amount int
+
+	// Type 週期種類 (PeriodDay, PeriodMonth, PeriodYear)
+	Type string
+
+	// Frequency 執行頻率 (D: 1 ~ 365, M: 1 ~ 12, Y: 1)
+	Frequency int
+
+	// ExecTimes 執行次數 (D: 2 ~ 999, M: 2 ~ 99, Y: 2 ~ 9)
+	ExecTimes int
+
+	// ReturnURL 每次授權結果通知網址 (PeriodReturnURL)
+	ReturnURL string
+
+	// UnionPay 銀聯卡交易選項
+	UnionPay int
+}
+
+func (p PeriodicPayment) Apply(e *ECPayTrade) error {
+
+	amount := p.Amount
+	if amount == 0 {
+		amount = e.TotalAmount
+	}
+	if amount != e.TotalAmount {
+		return invalidPayment("定期定額每次授權金額 %d 須與交易金額 %d 相同", amount, e.TotalAmount)
+	}
+
+	var maxFrequency, maxExecTimes int
+	switch p.Type {
+	case PeriodDay:
+		maxFrequency, maxExecTimes = 365, 999
+	case PeriodMonth:
+		maxFrequency, maxExecTimes = 12, 99
+	case PeriodYear:
+		maxFrequency, maxExecTimes = 1, 9
+	default:
+		return invalidPayment("不支援的定期定額週期種類 %q", p.Type)
+	}
+	if p.Frequency < 1 || p.Frequency > maxFrequency {
+		return invalidPayment("週期種類 %s 的執行頻率必須介於 1 ~ %d", p.Type, maxFrequency)
+	}
+	if p.ExecTimes < 2 || p.ExecTimes > maxExecTimes {
+		return invalidPayment("週期種類 %s 的執行次數必須介於 2 ~ %d", p.Type, maxExecTimes)
+	}
+	if p.UnionPay == UnionPayOnly {
+		return invalidPayment("銀聯卡不支援定期定額")
+	}
+
+	if err := e.choose(ChooseCredit); err != nil {
+		return err
+	}
+	e.PeriodAmount = amount
+	e.PeriodType = p.Type
+	e.Frequency = p.Frequency
+	e.ExecTimes = p.ExecTimes
+	e.PeriodReturnURL = p.ReturnURL
+	e.UnionPay = p.UnionPay
+	return nil
+}
+
+// PeriodResult is the per-period authorisation result ECPay posts to PeriodReturnURL
+type PeriodResult struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// StoreID 特店旗下店舖代號
+	StoreID string `json:"StoreID,omitempty" form:"StoreID"`
+
+	// RtnCode 授權結果 (1: 成功, 其餘為失敗)
+	RtnCode int `json:"RtnCode" form:"RtnCode"`
+
+	// RtnMsg 交易訊息
+	RtnMsg string `json:"RtnMsg,omitempty" form:"RtnMsg"`
+
+	// PeriodType 週期種類
+	PeriodType string `json:"PeriodType,omitempty" form:"PeriodType"`
+
+	// Frequency 執行頻率
+	Frequency int `json:"Frequency,omitempty" form:"Frequency"`
+
+	// ExecTimes 執行次數
+	ExecTimes int `json:"ExecTimes,omitempty" form:"ExecTimes"`
+
+	// Amount 本次授權金額
+	Amount int `json:"Amount,omitempty" form:"Amount"`
+
+	// Gwsr 授權交易單號
+	Gwsr int64 `json:"Gwsr,omitempty" form:"Gwsr"`
+
+	// ProcessDate 處理時間 (yyyy/MM/dd HH:mm:ss)
+	ProcessDate string `json:"ProcessDate,omitempty" form:"ProcessDate"`
+
+	// AuthCode 授權碼
+	AuthCode string `json:"AuthCode,omitempty" form:"AuthCode"`
+
+	// FirstAuthAmount 初次授權金額
+	FirstAuthAmount int `json:"FirstAuthAmount,omitempty" form:"FirstAuthAmount"`
+
+	// TotalSuccessTimes 已執行成功次數
+	TotalSuccessTimes int `json:"TotalSuccessTimes,omitempty" form:"TotalSuccessTimes"`
+
+	// SimulatePaid 是否為模擬付款
+	SimulatePaid int `json:"SimulatePaid,omitempty" form:"SimulatePaid"`
+
+	// CustomField1 自訂名稱欄位1
+	CustomField1 string `json:"CustomField1,omitempty" form:"CustomField1"`
+
+	// CustomField2 自訂名稱欄位2
+	CustomField2 string `json:"CustomField2,omitempty" form:"CustomField2"`
+
+	// CustomField3 自訂名稱欄位3
+	CustomField3 string `json:"CustomField3,omitempty" form:"CustomField3"`
+
+	// CustomField4 自訂名稱欄位4
+	CustomField4 string `json:"CustomField4,omitempty" form:"CustomField4"`
+
+	// CheckMacValue 檢查碼
+	CheckMacValue string `json:"CheckMacValue,omitempty" form:"CheckMacValue"`
+}
+
+// IsPaid reports whether this period was authorised successfully.
+func (p *PeriodResult) IsPaid() bool {
+	return p.RtnCode == 1
+}
+
+// PeriodNotificationHandler receives the per-period result ECPay posts to PeriodReturnURL.
+// The first authorisation is reported to ReturnURL instead; see NotificationHandler.
+type PeriodNotificationHandler struct {
+	// Client 提供驗證 CheckMacValue 的 HashKey / HashIV
+	Client *client.ECPayClient
+
+	// OnPeriod 收到已驗證的授權結果時呼叫, 回傳錯誤時綠界會重新通知
+	OnPeriod func(ctx context.Context, result *PeriodResult) error
+}
+
+func (h *PeriodNotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := &PeriodResult{}
+	notification.Serve(w, r, h.Client, result, func() error {
+		if h.OnPeriod == nil {
+			return nil
+		}
+		return h.OnPeriod(r.Context(), result)
+	})
+}
+
+// PeriodExecStatus 定期定額執行狀態
+const (
+	PeriodExecCanceled  = "0"
+	PeriodExecRunning   = "1"
+	PeriodExecCompleted = "2"
+)
+
+// PeriodInfo is the schedule and execution log of a periodic order
+type PeriodInfo struct {
+	MerchantID      string `json:"MerchantID"`
+	MerchantTradeNo string `json:"MerchantTradeNo"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo"`
+
+	// RtnCode 第一次授權結果
+	RtnCode int `json:"RtnCode"`
+
+	// PeriodType 週期種類
+	PeriodType string `json:"PeriodType"`
+
+	// Frequency 執行頻率
+	Frequency int `json:"Frequency"`
+
+	// ExecTimes 執行次數
+	ExecTimes int `json:"ExecTimes"`
+
+	// PeriodAmount 每次授權金額
+	PeriodAmount int `json:"PeriodAmount"`
+
+	// Amount 第一次授權金額
+	Amount int `json:"amount"`
+
+	// Gwsr 第一次授權交易單號
+	Gwsr int64 `json:"gwsr"`
+
+	// ProcessDate 第一次授權時間
+	ProcessDate string `json:"process_date"`
+
+	// AuthCode 第一次授權碼
+	AuthCode string `json:"auth_code"`
+
+	// Card4No 卡號末4碼
+	Card4No string `json:"card4no"`
+
+	// Card6No 卡號前6碼
+	Card6No string `json:"card6no"`
+
+	// TotalSuccessTimes 已成功授權次數
+	TotalSuccessTimes int `json:"TotalSuccessTimes"`
+
+	// TotalSuccessAmount 已成功授權總金額
+	TotalSuccessAmount int `json:"TotalSuccessAmount"`
+
+	// ExecStatus 執行狀態 (0: 已取消, 1: 執行中, 2: 執行完成)
+	ExecStatus string `json:"ExecStatus"`
+
+	// ExecLog 每次授權紀錄
+	ExecLog []PeriodExecLog `json:"ExecLog"`
+}
+
+// PeriodExecLog is one authorisation of a periodic order
+type PeriodExecLog struct {
+	RtnCode     int    `json:"RtnCode"`
+	Amount      int    `json:"amount"`
+	Gwsr        int64  `json:"gwsr"`
+	ProcessDate string `json:"process_date"`
+	AuthCode    string `json:"auth_code"`
+	TradeNo     string `json:"TradeNo"`
+}
+
+// QueryCreditCardPeriodInfo reads the schedule and execution log of this periodic order.
+func (e *ECPayTrade) QueryCreditCardPeriodInfo() (*PeriodInfo, error) {
+	return e.QueryCreditCardPeriodInfoContext(context.Background())
+}
+
+// QueryCreditCardPeriodInfoContext is like QueryCreditCardPeriodInfo but carries ctx to the outgoing request.
+func (e *ECPayTrade) QueryCreditCardPeriodInfoContext(ctx context.Context) (*PeriodInfo, error) {
+
+	query := &tradeInfoQuery{
+		MerchantID:      e.MerchantID,
+		MerchantTradeNo: e.MerchantTradeNo,
+		TimeStamp:       time.Now().Unix(),
+		PlatformID:      e.PlatformID,
+	}
+
+	body, err := sendSigned(ctx, e.Client, client.APIQueryCreditCardPeriodInfo, query)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PeriodInfo{}
+	if err = json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("error decoding period info: %w", err)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,50 @@
+package trade
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPeriodicPaymentLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment PeriodicPayment
+		wantErr bool
+	}{
+		{name: "day lower bound", payment: PeriodicPayment{Type: PeriodDay, Frequency: 1, ExecTimes: 2}},
+		{name: "day upper bound", payment: PeriodicPayment{Type: PeriodDay, Frequency: 365, ExecTimes: 999}},
+		{name: "day frequency too high", payment: PeriodicPayment{Type: PeriodDay, Frequency: 366, ExecTimes: 2}, wantErr: true},
+		{name: "day exec times too high", payment: PeriodicPayment{Type: PeriodDay, Frequency: 1, ExecTimes: 1000}, wantErr: true},
+		{name: "month upper bound", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 12, ExecTimes: 99}},
+		{name: "month frequency too high", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 13, ExecTimes: 2}, wantErr: true},
+		{name: "month exec times too high", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 1, ExecTimes: 100}, wantErr: true},
+		{name: "year upper bound", payment: PeriodicPayment{Type: PeriodYear, Frequency: 1, ExecTimes: 9}},
+		{name: "year frequency too high", payment: PeriodicPayment{Type: PeriodYear, Frequency: 2, ExecTimes: 2}, wantErr: true},
+		{name: "year exec times too high", payment: PeriodicPayment{Type: PeriodYear, Frequency: 1, ExecTimes: 10}, wantErr: true},
+		{name: "zero frequency", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 0, ExecTimes: 2}, wantErr: true},
+		{name: "single execution", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 1, ExecTimes: 1}, wantErr: true},
+		{name: "unknown type", payment: PeriodicPayment{Type: "W", Frequency: 1, ExecTimes: 2}, wantErr: true},
+		{name: "amount differs from total", payment: PeriodicPayment{Amount: 50, Type: PeriodMonth, Frequency: 1, ExecTimes: 2}, wantErr: true},
+		{name: "unionpay only", payment: PeriodicPayment{Type: PeriodMonth, Frequency: 1, ExecTimes: 2, UnionPay: UnionPayOnly}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ECPayTrade{TotalAmount: 100}
+			err := tt.payment.Apply(&e)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPaymentOption) {
+					t.Fatalf("Apply() error = %v, want ErrInvalidPaymentOption", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if e.ChoosePayment != ChooseCredit || e.PeriodAmount != 100 || e.PeriodType != tt.payment.Type ||
+				e.Frequency != tt.payment.Frequency || e.ExecTimes != tt.payment.ExecTimes {
+				t.Errorf("unexpected periodic fields %+v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package trade
+
+import (
+	"context"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"net/url"
+	"time"
+)
+
+// TradeStatus 交易狀態
+const (
+	TradeStatusUnpaid = "0"
+	TradeStatusPaid   = "1"
+
+	// TradeStatusFailed 訂單處理中或交易失敗
+	TradeStatusFailed = "10200095"
+)
+
+// TradeInfo is the order status returned by QueryTradeInfo
+type TradeInfo struct {
+
+	// Merchant 特店資訊
+	model.Merchant `json:",inline"`
+
+	// StoreID 特店旗下店舖代號
+	StoreID string `json:"StoreID,omitempty" form:"StoreID"`
+
+	// TradeNo 綠界的交易編號
+	TradeNo string `json:"TradeNo,omitempty" form:"TradeNo"`
+
+	// TradeAmt 交易金額
+	TradeAmt int `json:"TradeAmt,omitempty" form:"TradeAmt"`
+
+	// PaymentDate 付款時間 (yyyy/MM/dd HH:mm:ss)
+	PaymentDate string `json:"PaymentDate,omitempty" form:"PaymentDate"`
+
+	// PaymentType 特店選擇的付款方式
+	PaymentType string `json:"PaymentType,omitempty" form:"PaymentType"`
+
+	// HandlingCharge 手續費合計
+	HandlingCharge int `json:"HandlingCharge,omitempty" form:"HandlingCharge"`
+
+	// PaymentTypeChargeFee 交易手續費金額
+	PaymentTypeChargeFee int `json:"PaymentTypeChargeFee,omitempty" form:"PaymentTypeChargeFee"`
+
+	// TradeDate 訂單成立時間 (yyyy/MM/dd HH:mm:ss)
+	TradeDate string `json:"TradeDate,omitempty" form:"TradeDate"`
+
+	// TradeStatus 交易狀態 (0: 未付款, 1: 已付款, 10200095: 交易失敗)
+	TradeStatus string `json:"TradeStatus,omitempty" form:"TradeStatus"`
+
+	// ItemName 商品名稱
+	ItemName string `json:"ItemName,omitempty" form:"ItemName"`
+
+	// CustomField1 自訂名稱欄位1
+	CustomField1 string `json:"CustomField1,omitempty" form:"CustomField1"`
+
+	// CustomField2 自訂名稱欄位2
+	CustomField2 string `json:"CustomField2,omitempty" form:"CustomField2"`
+
+	// CustomField3 自訂名稱欄位3
+	CustomField3 string `json:"CustomField3,omitempty" form:"CustomField3"`
+
+	// CustomField4 自訂名稱欄位4
+	CustomField4 string `json:"CustomField4,omitempty" form:"CustomField4"`
+
+	// Gwsr 信用卡授權交易單號 (信用卡交易才有)
+	Gwsr int64 `json:"gwsr,omitempty" form:"gwsr"`
+
+	// ProcessDate 信用卡處理時間
+	ProcessDate string `json:"process_date,omitempty" form:"process_date"`
+
+	// AuthCode 信用卡授權碼
+	AuthCode string `json:"auth_code,omitempty" form:"auth_code"`
+
+	// Amount 信用卡授權金額
+	Amount int `json:"amount,omitempty" form:"amount"`
+
+	// Card4No 卡號末4碼
+	Card4No string `json:"card4no,omitempty" form:"card4no"`
+
+	// Card6No 卡號前6碼
+	Card6No string `json:"card6no,omitempty" form:"card6no"`
+
+	// CheckMacValue 檢查碼
+	CheckMacValue string `json:"CheckMacValue,omitempty" form:"CheckMacValue"`
+}
+
+// IsPaid reports whether ECPay considers the order paid.
+func (t *TradeInfo) IsPaid() bool {
+	return t.TradeStatus == TradeStatusPaid
+}
+
+// tradeInfoQuery is the request of the order lookup APIs keyed by MerchantTradeNo
+// (QueryTradeInfo, QueryPaymentInfo, QueryCreditCardPeriodInfo)
+type tradeInfoQuery struct {
+	MerchantID      string `form:"MerchantID"`
+	MerchantTradeNo string `form:"MerchantTradeNo"`
+	TimeStamp       int64  `form:"TimeStamp"`
+	PlatformID      string `form:"PlatformID"`
+}
+
+// QueryTradeInfo asks ECPay for the real status of this order (MerchantID + MerchantTradeNo).
+// The response CheckMacValue is verified before it is returned.
+func (e *ECPayTrade) QueryTradeInfo() (*TradeInfo, error) {
+	return e.QueryTradeInfoContext(context.Background())
+}
+
+// QueryTradeInfoContext is like QueryTradeInfo but carries ctx to the outgoing request.
+func (e *ECPayTrade) QueryTradeInfoContext(ctx context.Context) (*TradeInfo, error) {
+
+	query := &tradeInfoQuery{
+		MerchantID:      e.MerchantID,
+		MerchantTradeNo: e.MerchantTradeNo,
+		TimeStamp:       time.Now().Unix(),
+		PlatformID:      e.PlatformID,
+	}
+
+	body, err := sendSigned(ctx, e.Client, client.APIQueryTradeInfo, query)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseSignedResponse(e.Client, body)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TradeInfo{}
+	if err = helpers.BindFormValues(values, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// parseSignedResponse decodes an url-encoded ECPay reply and verifies its CheckMacValue.
+func parseSignedResponse(c *client.ECPayClient, body []byte) (url.Values, error) {
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	if err = validation.ValidateCheckMacValue(values, c.HashKey, c.HashIV); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
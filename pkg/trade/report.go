@@ -0,0 +1,428 @@
+package trade
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/client"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/transform"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ReportDateFormat 對帳檔查詢日期格式 (yyyy-MM-dd)
+const ReportDateFormat = "2006-01-02"
+
+// CharSet 對帳檔編碼
+const (
+	CharSetBig5 = 1
+	CharSetUTF8 = 2
+)
+
+// DateType 特店對帳媒體檔查詢日期類型
+const (
+	DateTypePayment  = 2
+	DateTypeAllocate = 4
+	DateTypeOrder    = 6
+)
+
+// PayDateType 信用卡撥款對帳資料檔查詢日期類型
+const (
+	PayDateTypeFund  = "fund"
+	PayDateTypeClose = "close"
+	PayDateTypeEnter = "enter"
+)
+
+// ErrReportFormat 對帳檔內容無法解析 (通常是綠界回傳了錯誤訊息而不是 CSV)
+var ErrReportFormat = errors.New("unrecognised reconciliation report")
+
+// TradeNoAioRequest downloads the daily order list (特店對帳媒體檔)
+type TradeNoAioRequest struct {
+
+	// BaseModel 通用參數
+	model.BaseModel `json:",inline"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID,omitempty" form:"MerchantID"`
+
+	// DateType 查詢日期類型 (2: 付款日期, 4: 撥款日期, 6: 訂單日期)
+	DateType int `json:"DateType,omitempty" form:"DateType"`
+
+	// BeginDate 查詢開始日期 (yyyy-MM-dd)
+	BeginDate string `json:"BeginDate,omitempty" form:"BeginDate"`
+
+	// EndDate 查詢結束日期 (yyyy-MM-dd)
+	EndDate string `json:"EndDate,omitempty" form:"EndDate"`
+
+	// PaymentType 付款方式 (01: 信用卡, 02: 網路ATM, 03: ATM, 04: 超商代碼, 05: 超商條碼...), 空白為全部
+	PaymentType string `json:"PaymentType,omitempty" form:"PaymentType"`
+
+	// PlatformStatus 訂單類型 (1: 一般, 2: 平台), 空白為全部
+	PlatformStatus string `json:"PlatformStatus,omitempty" form:"PlatformStatus"`
+
+	// PaymentStatus 付款狀態 (0: 未付款, 1: 已付款, 2: 訂單失敗), 空白為全部
+	PaymentStatus string `json:"PaymentStatus,omitempty" form:"PaymentStatus"`
+
+	// AllocateStatus 撥款狀態 (0: 未撥款, 1: 已撥款), 空白為全部
+	AllocateStatus string `json:"AllocateStatus,omitempty" form:"AllocateStatus"`
+
+	// MediaFormated 媒體檔格式 (0: 舊版, 1: 新版)
+	MediaFormated string `json:"MediaFormated,omitempty" form:"MediaFormated"`
+
+	// CharSet 檔案編碼 (1: Big5, 2: UTF-8), 未設定時使用 UTF-8
+	CharSet int `json:"CharSet,omitempty" form:"CharSet"`
+}
+
+// FundingReconDetailRequest downloads the credit card settlement detail (信用卡撥款對帳資料檔)
+type FundingReconDetailRequest struct {
+
+	// BaseModel 通用參數
+	model.BaseModel `json:",inline"`
+
+	// MerchantID 特店編號
+	MerchantID string `json:"MerchantID,omitempty" form:"MerchantID"`
+
+	// PayDateType 查詢日期類型 (fund: 撥款日期, close: 關帳日期, enter: 入帳日期)
+	PayDateType string `json:"PayDateType,omitempty" form:"PayDateType"`
+
+	// StartDate 查詢開始日期 (yyyy-MM-dd)
+	StartDate string `json:"StartDate,omitempty" form:"StartDate"`
+
+	// EndDate 查詢結束日期 (yyyy-MM-dd)
+	EndDate string `json:"EndDate,omitempty" form:"EndDate"`
+
+	// CharSet 檔案編碼 (1: Big5, 2: UTF-8), 未設定時使用 UTF-8
+	CharSet int `json:"CharSet,omitempty" form:"CharSet"`
+}
+
+// ReportRow is one trade of a reconciliation report
+type ReportRow struct {
+	// MerchantTradeNo 特店訂單編號
+	MerchantTradeNo string `json:"MerchantTradeNo"`
+
+	// TradeNo 綠界的交易編號 (信用卡撥款檔為授權單號)
+	TradeNo string `json:"TradeNo"`
+
+	// PaymentType 付款方式
+	PaymentType string `json:"PaymentType"`
+
+	// PaymentStatus 付款狀態 (已付款, 未付款, 訂單失敗...), 報表沒有此欄位時為空白
+	PaymentStatus string `json:"PaymentStatus"`
+
+	// Amount 交易金額
+	Amount int `json:"Amount"`
+
+	// Fee 手續費
+	Fee int `json:"Fee"`
+
+	// NetAmount 撥款金額 (交易金額扣除手續費)
+	NetAmount int `json:"NetAmount"`
+
+	// PaymentDate 付款時間
+	PaymentDate string `json:"PaymentDate"`
+
+	// SettlementDate 撥款日期
+	SettlementDate string `json:"SettlementDate"`
+
+	// Fields 以表頭為鍵的原始欄位
+	Fields map[string]string `json:"Fields"`
+}
+
+// ReportLayout maps ReportRow field names to the header of that column in a report
+type ReportLayout map[string]string
+
+// TradeNoAioLayout 特店對帳媒體檔 (新版, MediaFormated=1) 的表頭
+var TradeNoAioLayout = ReportLayout{
+	"MerchantTradeNo": "特店訂單編號",
+	"TradeNo":         "綠界訂單編號",
+	"PaymentType":     "付款方式",
+	"PaymentStatus":   "付款狀態",
+	"Amount":          "交易金額",
+	"Fee":             "交易手續費",
+	"NetAmount":       "應收款項(淨額)",
+	"PaymentDate":     "付款時間",
+	"SettlementDate":  "撥款日期",
+}
+
+// FundingReconLayout 信用卡撥款對帳資料檔的表頭
+var FundingReconLayout = ReportLayout{
+	"MerchantTradeNo": "特店訂單編號",
+	"TradeNo":         "授權單號",
+	"Amount":          "授權金額",
+	"Fee":             "手續費",
+	"NetAmount":       "撥款金額",
+	"PaymentDate":     "授權日期",
+	"SettlementDate":  "撥款日期",
+}
+
+// ReportRows streams the rows of a reconciliation report, in the style of bufio.Scanner:
+//
+//	rows, err := request.Download()
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//	}
+//	err = rows.Err()
+type ReportRows struct {
+	body    io.ReadCloser
+	reader  *csv.Reader
+	layout  ReportLayout
+	columns map[string]int
+	header  []string
+	row     ReportRow
+	err     error
+}
+
+// Download fetches the order list of the date range and streams its rows.
+func (r *TradeNoAioRequest) Download() (*ReportRows, error) {
+	return r.DownloadContext(context.Background())
+}
+
+// DownloadContext is like Download but carries ctx to the outgoing request.
+// The defaults are filled in on a copy, r is left unchanged.
+func (r *TradeNoAioRequest) DownloadContext(ctx context.Context) (*ReportRows, error) {
+	request := *r
+	if request.CharSet == 0 {
+		request.CharSet = CharSetUTF8
+	}
+	if request.MediaFormated == "" {
+		request.MediaFormated = "1"
+	}
+	return openReport(ctx, request.Client, client.APITradeNoAio, &request, request.CharSet, TradeNoAioLayout)
+}
+
+// Download fetches the settlement detail of the date range and streams its rows.
+func (r *FundingReconDetailRequest) Download() (*ReportRows, error) {
+	return r.DownloadContext(context.Background())
+}
+
+// DownloadContext is like Download but carries ctx to the outgoing request.
+// The defaults are filled in on a copy, r is left unchanged.
+func (r *FundingReconDetailRequest) DownloadContext(ctx context.Context) (*ReportRows, error) {
+	request := *r
+	if request.CharSet == 0 {
+		request.CharSet = CharSetUTF8
+	}
+	return openReport(ctx, request.Client, client.APIFundingReconDetail, &request, request.CharSet, FundingReconLayout)
+}
+
+// openReport signs the download request (對帳檔使用 MD5) and wraps the response in a row iterator.
+func openReport(ctx context.Context, c *client.ECPayClient, api client.API, request any, charSet int, layout ReportLayout) (*ReportRows, error) {
+
+	formData := helpers.ReflectFormValues(request)
+	formData.Del("CheckMacValue")
+	c.DefaultMerchantID(formData)
+
+	checkMacValue := helpers.GenerateCheckMacValue(formData, c.HashKey, c.HashIV,
+		helpers.WithHashAlgorithm(helpers.HashMD5),
+		helpers.WithLogger(c.SigningLog()))
+	formData.Set("CheckMacValue", checkMacValue)
+
+	body, err := helpers.OpenFormDataContext(ctx, c, api, formData)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReportRows(body, charSet, layout), nil
+}
+
+// NewReportRows parses a report already on hand, e.g. one downloaded from the vendor portal.
+// layout names the headers of the report, such as TradeNoAioLayout or FundingReconLayout.
+func NewReportRows(body io.ReadCloser, charSet int, layout ReportLayout) *ReportRows {
+
+	var reader io.Reader = body
+	if charSet == CharSetBig5 {
+		reader = transform.NewReader(body, traditionalchinese.Big5.NewDecoder())
+	}
+
+	csvReader := csv.NewReader(bufio.NewReader(reader))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	return &ReportRows{body: body, reader: csvReader, layout: layout}
+}
+
+// Next advances to the next row, returning false at the end of the report or on error.
+func (it *ReportRows) Next() bool {
+
+	if it.err != nil {
+		return false
+	}
+
+	for {
+		record, err := it.reader.Read()
+		if err == io.EOF {
+			if it.columns == nil {
+				it.err = ErrReportFormat
+			}
+			return false
+		}
+		if err != nil {
+			it.err = fmt.Errorf("error reading report: %w", err)
+			return false
+		}
+
+		for i := range record {
+			record[i] = cleanReportField(record[i])
+		}
+
+		// 表頭前可能有標題列, 找到可辨識的表頭後才開始解析
+		if it.columns == nil {
+			if columns := reportHeader(record, it.layout); columns != nil {
+				it.columns = columns
+				it.header = record
+			} else if len(record) == 1 && strings.Contains(record[0], "|") {
+				it.err = fmt.Errorf("%w: %s", ErrReportFormat, record[0])
+				return false
+			}
+			continue
+		}
+
+		row, ok, err := it.parse(record)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !ok {
+			continue
+		}
+		it.row = row
+		return true
+	}
+}
+
+// Row returns the row read by the last call to Next.
+func (it *ReportRows) Row() ReportRow {
+	return it.row
+}
+
+// Err returns the first error encountered while reading.
+func (it *ReportRows) Err() error {
+	return it.err
+}
+
+// Close releases the underlying response body.
+func (it *ReportRows) Close() error {
+	return it.body.Close()
+}
+
+// parse maps record onto a row. ok is false for rows that carry no trade
+// (blank and total lines); an amount that cannot be read is an error.
+func (it *ReportRows) parse(record []string) (row ReportRow, ok bool, err error) {
+
+	get := func(name string) string {
+		if i, ok := it.columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	row = ReportRow{
+		MerchantTradeNo: get("MerchantTradeNo"),
+		TradeNo:         get("TradeNo"),
+		PaymentType:     get("PaymentType"),
+		PaymentStatus:   get("PaymentStatus"),
+		PaymentDate:     get("PaymentDate"),
+		SettlementDate:  get("SettlementDate"),
+		Fields:          make(map[string]string, len(record)),
+	}
+
+	// 空白列與合計列沒有訂單編號
+	if row.MerchantTradeNo == "" && row.TradeNo == "" || isReportTotal(record) {
+		return ReportRow{}, false, nil
+	}
+
+	amounts := []struct {
+		name string
+		dst  *int
+	}{{"Amount", &row.Amount}, {"Fee", &row.Fee}, {"NetAmount", &row.NetAmount}}
+	for _, amount := range amounts {
+		if *amount.dst, err = parseReportAmount(get(amount.name)); err != nil {
+			return ReportRow{}, false, fmt.Errorf("%w: %s (MerchantTradeNo %q, TradeNo %q): %v",
+				ErrReportFormat, amount.name, row.MerchantTradeNo, row.TradeNo, err)
+		}
+	}
+
+	if row.NetAmount == 0 && get("NetAmount") == "" {
+		row.NetAmount = row.Amount - row.Fee
+	}
+	for i, name := range it.header {
+		if i < len(record) {
+			row.Fields[name] = record[i]
+		}
+	}
+
+	return row, true, nil
+}
+
+// reportHeader maps column names to indexes when record is the header of layout.
+func reportHeader(record []string, layout ReportLayout) map[string]int {
+
+	columns := map[string]int{}
+	for name, header := range layout {
+		if i := indexOf(record, header); i >= 0 {
+			columns[name] = i
+		}
+	}
+
+	_, hasMerchantTradeNo := columns["MerchantTradeNo"]
+	_, hasTradeNo := columns["TradeNo"]
+	if !hasMerchantTradeNo && !hasTradeNo {
+		return nil
+	}
+	return columns
+}
+
+// isReportTotal reports whether record is a total line such as 合計 at the end of the report.
+func isReportTotal(record []string) bool {
+	for _, label := range []string{"合計", "總計", "小計"} {
+		if len(record) > 0 && strings.HasPrefix(record[0], label) {
+			return true
+		}
+	}
+	return false
+}
+
+func indexOf(record []string, value string) int {
+	for i, field := range record {
+		if field == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// cleanReportField strips the BOM, whitespace and the ="..." wrapper ECPay uses
+// to keep Excel from dropping leading zeros.
+func cleanReportField(field string) string {
+	field = strings.TrimPrefix(field, "\ufeff")
+	field = strings.TrimSpace(field)
+	if strings.HasPrefix(field, `="`) && strings.HasSuffix(field, `"`) {
+		field = field[2 : len(field)-1]
+	}
+	return strings.TrimSpace(field)
+}
+
+// parseReportAmount reads an amount such as "1,200", "NT$ 1200" or "1200.00".
+// An empty field is 0; anything else that is not a number is an error.
+func parseReportAmount(field string) (int, error) {
+	cleaned := strings.NewReplacer(",", "", "$", "", "NT", "", " ", "").Replace(field)
+	if cleaned == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(cleaned); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", field)
+	}
+	return int(math.Round(f)), nil
+}
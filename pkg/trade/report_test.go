@@ -0,0 +1,164 @@
+package trade
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/model"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/validation"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"io"
+	"strings"
+	"testing"
+)
+
+// tradeNoAioReport starts with a BOM right before the header, as ECPay's UTF-8 files do
+const tradeNoAioReport = "\ufeff特店訂單編號,綠界訂單編號,付款方式,付款狀態,金額,交易金額,交易手續費,應收款項(淨額),付款時間,撥款日期\n" +
+	"=\"00123\",2401011200000001,Credit_CreditCard,已付款,1,\"1,200\",24,\"1,176\",2024/01/01 12:00:00,2024/01/08\n" +
+	"\n" +
+	"A2,2401011300000002,ATM_TAISHIN,未付款,1,500,0,,2024/01/01 13:00:00,\n" +
+	"合計,,,,,\"1,700\",24,\"1,176\",,\n"
+
+func readReport(t *testing.T, rows *ReportRows) []ReportRow {
+	t.Helper()
+	defer rows.Close()
+
+	var got []ReportRow
+	for rows.Next() {
+		got = append(got, rows.Row())
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	return got
+}
+
+func checkTradeNoAioRows(t *testing.T, got []ReportRow) {
+	t.Helper()
+
+	if len(got) != 2 {
+		t.Fatalf("read %d rows, want 2 (footer and blank lines skipped): %+v", len(got), got)
+	}
+
+	first := got[0]
+	if first.MerchantTradeNo != "00123" || first.TradeNo != "2401011200000001" || first.PaymentStatus != "已付款" {
+		t.Errorf("first row = %+v", first)
+	}
+	// 交易金額 is bound, not the generic 金額 column before it
+	if first.Amount != 1200 || first.Fee != 24 || first.NetAmount != 1176 {
+		t.Errorf("first row amounts = %d, %d, %d", first.Amount, first.Fee, first.NetAmount)
+	}
+	if first.Fields["付款方式"] != "Credit_CreditCard" || first.SettlementDate != "2024/01/08" {
+		t.Errorf("first row fields = %+v", first.Fields)
+	}
+
+	// 沒有淨額時以交易金額扣除手續費
+	if got[1].MerchantTradeNo != "A2" || got[1].Amount != 500 || got[1].NetAmount != 500 {
+		t.Errorf("second row = %+v", got[1])
+	}
+}
+
+func TestReportRowsUTF8(t *testing.T) {
+	rows := NewReportRows(io.NopCloser(strings.NewReader(tradeNoAioReport)), CharSetUTF8, TradeNoAioLayout)
+	checkTradeNoAioRows(t, readReport(t, rows))
+}
+
+func TestReportRowsBig5(t *testing.T) {
+	// Big5 沒有 BOM
+	encoded, err := traditionalchinese.Big5.NewEncoder().String(strings.TrimPrefix(tradeNoAioReport, "\ufeff"))
+	if err != nil {
+		t.Fatalf("encoding Big5: %v", err)
+	}
+	rows := NewReportRows(io.NopCloser(strings.NewReader(encoded)), CharSetBig5, TradeNoAioLayout)
+	checkTradeNoAioRows(t, readReport(t, rows))
+}
+
+func TestReportRowsFundingRecon(t *testing.T) {
+	report := "信用卡撥款對帳資料檔\n" +
+		"授權日期,關帳日期,撥款日期,特店訂單編號,授權單號,授權金額,手續費,撥款金額\n" +
+		"2024/01/01,2024/01/02,2024/01/08,A1,12345678,1000,20,980\n"
+	rows := NewReportRows(io.NopCloser(strings.NewReader(report)), CharSetUTF8, FundingReconLayout)
+	got := readReport(t, rows)
+	if len(got) != 1 {
+		t.Fatalf("read %d rows, want 1", len(got))
+	}
+	if row := got[0]; row.MerchantTradeNo != "A1" || row.TradeNo != "12345678" || row.Amount != 1000 ||
+		row.Fee != 20 || row.NetAmount != 980 || row.PaymentDate != "2024/01/01" || row.SettlementDate != "2024/01/08" {
+		t.Errorf("row = %+v", row)
+	}
+}
+
+func TestReportRowsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+	}{
+		{name: "error message", report: "0|CheckMacValue Error\n"},
+		{name: "no header", report: "交易編號,金額\n1,100\n"},
+		{name: "empty", report: ""},
+		{name: "invalid amount", report: "特店訂單編號,綠界訂單編號,交易金額\nA1,1,abc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := NewReportRows(io.NopCloser(strings.NewReader(tt.report)), CharSetUTF8, TradeNoAioLayout)
+			defer rows.Close()
+			for rows.Next() {
+			}
+			if !errors.Is(rows.Err(), ErrReportFormat) {
+				t.Errorf("Err() = %v, want ErrReportFormat", rows.Err())
+			}
+		})
+	}
+}
+
+func TestParseReportAmount(t *testing.T) {
+	tests := []struct {
+		field   string
+		want    int
+		wantErr bool
+	}{
+		{field: "", want: 0},
+		{field: "1200", want: 1200},
+		{field: "1,200", want: 1200},
+		{field: "NT$ 1,200", want: 1200},
+		{field: "1200.00", want: 1200},
+		{field: "12.5", want: 13},
+		{field: "-30", want: -30},
+		{field: "abc", wantErr: true},
+		{field: "1.2.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseReportAmount(tt.field)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseReportAmount(%q) = %d, %v; want %d, wantErr %v", tt.field, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTradeNoAioDownload(t *testing.T) {
+	doer := &formDoer{reply: tradeNoAioReport}
+	r := &TradeNoAioRequest{
+		BaseModel: model.BaseModel{Client: clientWith(doer)},
+		DateType:  DateTypePayment,
+		BeginDate: "2024-01-01",
+		EndDate:   "2024-01-31",
+	}
+
+	rows, err := r.Download()
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	checkTradeNoAioRows(t, readReport(t, rows))
+
+	if r.CharSet != 0 || r.MediaFormated != "" {
+		t.Errorf("Download() changed the request: %+v", r)
+	}
+	if doer.sent.Get("CharSet") != "2" || doer.sent.Get("MediaFormated") != "1" || doer.sent.Get("MerchantID") != r.Client.MerchantID {
+		t.Errorf("sent %v", doer.sent)
+	}
+	if err := validation.ValidateCheckMacValue(doer.sent, r.Client.HashKey, r.Client.HashIV,
+		helpers.WithHashAlgorithm(helpers.HashMD5)); err != nil {
+		t.Errorf("request is not signed with MD5: %v", err)
+	}
+}
@@ -1,28 +1,97 @@
 package validation
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
-// ValidateCheckMacValue validates the CheckMacValue from ECPay's response
-func ValidateCheckMacValue(responseValues url.Values, hashKey string, hashIV string) error {
+var (
+	// ErrMissingCheckMac 回應中沒有 CheckMacValue
+	ErrMissingCheckMac = errors.New("CheckMacValue is missing from the response")
+
+	// ErrCheckMacMismatch CheckMacValue 驗證失敗
+	ErrCheckMacMismatch = errors.New("CheckMacValue mismatch")
+)
+
+// ValidateCheckMacValue validates the CheckMacValue from ECPay's response.
+//
+// Both SHA256 and MD5 are supported, one per call: the caller names the algorithm the
+// API signs with through helpers.WithHashAlgorithm (SHA256 by default, HashMD5 for the
+// logistics APIs). Refusing a downgrade is intended: the algorithm is never inferred from
+// the received value, and a value of the other algorithm (or length) is rejected, so a
+// forged MD5 value cannot pass on a SHA256 endpoint. responseValues is left untouched.
+func ValidateCheckMacValue(responseValues url.Values, hashKey string, hashIV string, opts ...helpers.CheckMacOption) error {
 	// Extract the CheckMacValue from the response
 	receivedCheckMacValue := responseValues.Get("CheckMacValue")
 	if receivedCheckMacValue == "" {
-		return errors.New("CheckMacValue is missing from the response")
+		return ErrMissingCheckMac
 	}
 
-	// Remove the CheckMacValue from the values as it's not included in the generation
-	responseValues.Del("CheckMacValue")
+	// CheckMacValue 不列入檢查碼計算, 複製一份避免修改呼叫端的資料
+	values := make(url.Values, len(responseValues))
+	for key, value := range responseValues {
+		if key != "CheckMacValue" {
+			values[key] = value
+		}
+	}
 
-	// Generate the CheckMacValue using the provided values
-	expectedCheckMacValue := helpers.GenerateCheckMacValue(responseValues, hashKey, hashIV)
+	expectedCheckMacValue := helpers.GenerateCheckMacValue(values, hashKey, hashIV, opts...)
 
-	if expectedCheckMacValue != receivedCheckMacValue {
-		return errors.New("CheckMacValue mismatch")
+	// 綠界有時回傳小寫的檢查碼, 統一轉大寫後以固定時間比較; 長度不同 (演算法不同) 時必定不相符
+	if subtle.ConstantTimeCompare([]byte(expectedCheckMacValue), []byte(strings.ToUpper(receivedCheckMacValue))) != 1 {
+		return ErrCheckMacMismatch
 	}
 
 	return nil
 }
+
+// ValidateJSONCheckMacValue validates the CheckMacValue of a JSON-bodied callback.
+// Every top-level field except CheckMacValue takes part in the signature.
+func ValidateJSONCheckMacValue(body []byte, hashKey string, hashIV string, opts ...helpers.CheckMacOption) error {
+	values, err := JSONToValues(body)
+	if err != nil {
+		return err
+	}
+	return ValidateCheckMacValue(values, hashKey, hashIV, opts...)
+}
+
+// JSONToValues flattens a JSON object into url.Values the way ECPay signs it:
+// numbers keep their literal form, booleans become "true"/"false" and null becomes "".
+func JSONToValues(body []byte) (url.Values, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	fields := map[string]any{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("error decoding JSON body: %w", err)
+	}
+
+	values := make(url.Values, len(fields))
+	for key, field := range fields {
+		switch v := field.(type) {
+		case nil:
+			values.Set(key, "")
+		case string:
+			values.Set(key, v)
+		case json.Number:
+			values.Set(key, v.String())
+		case bool:
+			values.Set(key, strconv.FormatBool(v))
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding field %s: %w", key, err)
+			}
+			values.Set(key, string(raw))
+		}
+	}
+
+	return values, nil
+}
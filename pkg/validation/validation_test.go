@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"errors"
+	"github.com/EcomPlatformOrg/ecpay-go/pkg/helpers"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	testHashKey = "pwFHCqoQZGmho4w6"
+	testHashIV  = "EkRm7iFT261dpevs"
+)
+
+func signedValues(algorithm helpers.HashAlgorithm) url.Values {
+	values := url.Values{
+		"MerchantID":      {"3002607"},
+		"MerchantTradeNo": {"ecpay20230312153023"},
+		"RtnCode":         {"1"},
+		"RtnMsg":          {"交易成功"},
+		"TradeAmt":        {"30000"},
+	}
+	values.Set("CheckMacValue", helpers.GenerateCheckMacValue(values, testHashKey, testHashIV, helpers.WithHashAlgorithm(algorithm)))
+	return values
+}
+
+func TestValidateCheckMacValue(t *testing.T) {
+	md5 := helpers.WithHashAlgorithm(helpers.HashMD5)
+
+	tests := []struct {
+		name   string
+		values func() url.Values
+		opts   []helpers.CheckMacOption
+		want   error
+	}{
+		{name: "sha256", values: func() url.Values { return signedValues(helpers.HashSHA256) }},
+		{name: "md5 when md5 is expected", values: func() url.Values { return signedValues(helpers.HashMD5) }, opts: []helpers.CheckMacOption{md5}},
+		{
+			name:   "md5 rejected on the sha256 path",
+			values: func() url.Values { return signedValues(helpers.HashMD5) },
+			want:   ErrCheckMacMismatch,
+		},
+		{
+			name:   "sha256 rejected on the md5 path",
+			values: func() url.Values { return signedValues(helpers.HashSHA256) },
+			opts:   []helpers.CheckMacOption{md5},
+			want:   ErrCheckMacMismatch,
+		},
+		{
+			name: "lowercase value",
+			values: func() url.Values {
+				values := signedValues(helpers.HashSHA256)
+				values.Set("CheckMacValue", strings.ToLower(values.Get("CheckMacValue")))
+				return values
+			},
+		},
+		{
+			name: "tampered field",
+			values: func() url.Values {
+				values := signedValues(helpers.HashSHA256)
+				values.Set("TradeAmt", "1")
+				return values
+			},
+			want: ErrCheckMacMismatch,
+		},
+		{
+			name: "missing value",
+			values: func() url.Values {
+				values := signedValues(helpers.HashSHA256)
+				values.Del("CheckMacValue")
+				return values
+			},
+			want: ErrMissingCheckMac,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := tt.values()
+			before := values.Encode()
+
+			err := ValidateCheckMacValue(values, testHashKey, testHashIV, tt.opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ValidateCheckMacValue() = %v, want %v", err, tt.want)
+			}
+			if values.Encode() != before {
+				t.Error("ValidateCheckMacValue modified the values")
+			}
+		})
+	}
+}
+
+func TestValidateJSONCheckMacValue(t *testing.T) {
+	values := url.Values{"MerchantID": {"3002607"}, "RtnCode": {"1"}, "Amount": {"100"}, "Paid": {"true"}}
+	mac := helpers.GenerateCheckMacValue(values, testHashKey, testHashIV)
+	body := `{"MerchantID":"3002607","RtnCode":1,"Amount":100,"Paid":true,"CheckMacValue":"` + mac + `"}`
+
+	if err := ValidateJSONCheckMacValue([]byte(body), testHashKey, testHashIV); err != nil {
+		t.Errorf("ValidateJSONCheckMacValue() = %v", err)
+	}
+
+	tampered := strings.Replace(body, `"Amount":100`, `"Amount":1`, 1)
+	if err := ValidateJSONCheckMacValue([]byte(tampered), testHashKey, testHashIV); !errors.Is(err, ErrCheckMacMismatch) {
+		t.Errorf("tampered body: ValidateJSONCheckMacValue() = %v, want %v", err, ErrCheckMacMismatch)
+	}
+}